@@ -127,6 +127,12 @@ const docTemplate = `{
                         "description": "Access Token (optional; if not provided, the server will try to get the access token from the request header)",
                         "name": "access_token",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Websocket subprotocol (ondaum.json.v1 or ondaum.msgpack.v1; defaults to ondaum.json.v1)",
+                        "name": "Sec-WebSocket-Protocol",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -462,6 +468,166 @@ const docTemplate = `{
                 }
             }
         },
+        "/chats/{session_id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events fallback for the chat websocket. Emits the same frames (notify, status, data, reject) as events named after their action, with the history sequence as the event id. Reconnect with Last-Event-ID to replay missed replies.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "chat"
+                ],
+                "summary": "Stream chat events",
+                "operationId": "GetChatEvents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID (the most recent non-archived conversation is used if one exists, like the websocket)",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Access Token (optional; for clients that cannot set the Authorization header)",
+                        "name": "access_token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last received sequence to resume after",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/websocket.ResponseWrapper"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/http.Error"
+                        }
+                    }
+                }
+            }
+        },
+        "/chats/{session_id}/messages": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Send a chat action (defaults to \"chat\") without a websocket. Results are delivered to the session's event stream (GET /chats/{session_id}/events) and any open websocket. The session must be the conversation the event stream resolved to, otherwise 409 is returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chat"
+                ],
+                "summary": "Post chat message",
+                "operationId": "PostChatMessage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "session_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Chat action",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/chat.PostChatMessageHandlerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/chat.PostChatMessageHandlerResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/http.Error"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/http.Error"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/http.Error"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/http.Error"
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "$ref": "#/definitions/http.Error"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/http.Error"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/http.Error"
+                        }
+                    }
+                }
+            }
+        },
         "/chats/{session_id}/summary": {
             "post": {
                 "security": [
@@ -1157,6 +1323,9 @@ const docTemplate = `{
                 "id": {
                     "type": "string"
                 },
+                "is_cancelled": {
+                    "type": "boolean"
+                },
                 "metadata": {
                     "type": "array",
                     "items": {
@@ -1166,6 +1335,9 @@ const docTemplate = `{
                 "role": {
                     "type": "string"
                 },
+                "sequence": {
+                    "type": "integer"
+                },
                 "when": {
                     "type": "string"
                 }
@@ -1182,6 +1354,31 @@ const docTemplate = `{
                 }
             }
         },
+        "chat.PostChatMessageHandlerRequest": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/websocket.Action"
+                        }
+                    ],
+                    "example": "chat"
+                },
+                "payload": {
+                    "type": "string",
+                    "example": "Hello"
+                }
+            }
+        },
+        "chat.PostChatMessageHandlerResponse": {
+            "type": "object",
+            "properties": {
+                "message_id": {
+                    "type": "string"
+                }
+            }
+        },
         "chat.PredefinedStressLevel": {
             "type": "object",
             "properties": {
@@ -1660,13 +1857,17 @@ const docTemplate = `{
                 "noop",
                 "reject",
                 "data",
-                "notify"
+                "notify",
+                "authenticate",
+                "auth_expiring"
             ],
             "x-enum-varnames": [
                 "PredefinedActionNoop",
                 "PredefinedActionReject",
                 "PredefinedActionData",
-                "PredefinedActionNotify"
+                "PredefinedActionNotify",
+                "PredefinedActionAuthenticate",
+                "PredefinedActionAuthExpiring"
             ]
         },
         "websocket.ResponseWrapper": {
@@ -1679,6 +1880,15 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "payload": {},
+                "protocol": {
+                    "type": "string"
+                },
+                "reconnect_after_ms": {
+                    "type": "integer"
+                },
+                "sequence": {
+                    "type": "integer"
+                },
                 "session_id": {
                     "type": "string"
                 }
@@ -13,10 +13,21 @@ import (
 func NewFutureModule(config future.Config, process ...fx.Option) fx.Option {
 	return fx.Module("future",
 		fx.Provide(func(db *bun.DB, clk clock.Clock) future.Core {
-			return dbfuture.NewCore(db, clk)
+			return dbfuture.NewCore(db, clk, future.NewWorkerID(), config.Lease.Duration)
 		}),
-		fx.Provide(func(core future.Core) *future.Scheduler {
-			return future.NewScheduler(config, core)
+		fx.Provide(func() *future.AdminAlertSink {
+			return future.NewAdminAlertSink(config.Alert.Admin.Capacity)
+		}),
+		fx.Provide(func(core future.Core, admin *future.AdminAlertSink, clk clock.Clock) *future.Scheduler {
+			scheduler := future.NewScheduler(config, core, clk)
+			if config.Alert.Log {
+				scheduler.AddAlertSink(future.NewLogAlertSink())
+			}
+			if config.Alert.Webhook.URL != "" {
+				scheduler.AddAlertSink(future.NewWebhookAlertSink(config.Alert.Webhook.URL, config.Alert.Webhook.Timeout))
+			}
+			scheduler.AddAlertSink(admin)
+			return scheduler
 		}),
 		fx.Options(process...),
 		fx.Invoke(func(lc fx.Lifecycle, scheduler *future.Scheduler) {
@@ -25,9 +36,8 @@ func NewFutureModule(config future.Config, process ...fx.Option) fx.Option {
 					scheduler.Start()
 					return nil
 				},
-				OnStop: func(_ context.Context) error {
-					scheduler.Stop()
-					return nil
+				OnStop: func(ctx context.Context) error {
+					return scheduler.Stop(ctx)
 				},
 			})
 		}),
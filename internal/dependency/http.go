@@ -84,3 +84,13 @@ func HttpRoute[H http.Handler, DEP any](method string, path string, constructor
 		}),
 	)
 }
+
+// AdminHttpRoute registers an operator route that requires the configured admin token.
+func AdminHttpRoute[H http.Handler, DEP any](method string, path string, constructor func(dependencies DEP) (H, error)) fx.Option {
+	return fx.Options(
+		fx.Provide(fx.Private, constructor),
+		fx.Invoke(func(router fiber.Router, instance H, config http.Config) {
+			router.Add(method, path, http.NewAdminGuardMiddleware(config.Admin), instance.Handle).Name(instance.Identify())
+		}),
+	)
+}
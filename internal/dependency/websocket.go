@@ -1,10 +1,16 @@
 package dependency
 
 import (
+	"context"
+
+	"github.com/benbjohnson/clock"
 	"github.com/gofiber/fiber/v2"
 	"github.com/solutionchallenge/ondaum-server/pkg/jwt"
 	"github.com/solutionchallenge/ondaum-server/pkg/utils"
 	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
+	dbbackplane "github.com/solutionchallenge/ondaum-server/pkg/websocket/backplane/database"
+	membackplane "github.com/solutionchallenge/ondaum-server/pkg/websocket/backplane/memory"
+	"github.com/uptrace/bun"
 	"go.uber.org/fx"
 )
 
@@ -12,13 +18,32 @@ type WebsocketCoreManager struct {
 	ConfiguredCoreMap map[string]struct{}
 }
 
-func NewWebsocketModule(routes ...fx.Option) fx.Option {
+func NewWebsocketModule(config wspkg.Config, routes ...fx.Option) fx.Option {
 	return fx.Module("websocket",
 		fx.Supply(
 			&WebsocketCoreManager{
 				ConfiguredCoreMap: make(map[string]struct{}),
 			},
 		),
+		fx.Provide(func(db *bun.DB, clk clock.Clock) (wspkg.Backplane, error) {
+			return instantiateWebsocketBackplane(config, db, clk)
+		}),
+		fx.Provide(func(backplane wspkg.Backplane) *wspkg.Hub {
+			return wspkg.NewHub(config, backplane)
+		}),
+		fx.Invoke(func(lc fx.Lifecycle, backplane wspkg.Backplane, hub *wspkg.Hub) {
+			lc.Append(fx.Hook{
+				OnStart: func(ctx context.Context) error {
+					return backplane.Subscribe(ctx, hub.Deliver)
+				},
+				OnStop: func(ctx context.Context) error {
+					if err := hub.Drain(ctx); err != nil {
+						utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to drain websocket connections")
+					}
+					return backplane.Close()
+				},
+			})
+		}),
 		fx.Options(routes...),
 	)
 }
@@ -27,14 +52,14 @@ func WebsocketRoute[DEP any, H wspkg.Handler](path string, constructor func(depe
 	return fx.Options(
 		fx.Provide(fx.Private, constructor),
 		fx.Invoke(
-			func(manager *WebsocketCoreManager, router fiber.Router, jwt jwt.Generator, handler H) {
+			func(manager *WebsocketCoreManager, router fiber.Router, jwt jwt.Generator, hub *wspkg.Hub, handler H) {
 				if _, ok := manager.ConfiguredCoreMap[path]; !ok {
 					manager.ConfiguredCoreMap[path] = struct{}{}
 					err := wspkg.EnableWebsocketCore(router, path, jwt)
 					if err != nil {
 						panic(err)
 					}
-					wspkg.Install(router, path, handler)
+					wspkg.Install(router, path, handler, hub, jwt)
 				} else {
 					panic(utils.NewError("websocket core already configured for path %s", path))
 				}
@@ -42,3 +67,14 @@ func WebsocketRoute[DEP any, H wspkg.Handler](path string, constructor func(depe
 		),
 	)
 }
+
+func instantiateWebsocketBackplane(config wspkg.Config, db *bun.DB, clk clock.Clock) (wspkg.Backplane, error) {
+	switch config.Backplane.Kind {
+	case "", membackplane.Kind:
+		return membackplane.NewBackplane(), nil
+	case dbbackplane.Kind:
+		return dbbackplane.NewBackplane(db, clk, config.Backplane), nil
+	default:
+		return nil, utils.NewError("unsupported websocket backplane: %s", config.Backplane.Kind)
+	}
+}
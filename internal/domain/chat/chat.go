@@ -21,6 +21,7 @@ type Chat struct {
 	FinishedAt    bun.NullTime            `json:"finished_at" db:"finished_at" bun:"finished_at"`
 	ArchivedAt    bun.NullTime            `json:"archived_at" db:"archived_at" bun:"archived_at"`
 	ChatDuration  common.NullableDuration `json:"chat_duration" db:"chat_duration" bun:"chat_duration"`
+	AckedSequence int64                   `json:"acked_sequence" db:"acked_sequence" bun:"acked_sequence,notnull"`
 
 	User      *user.User `json:"user,omitempty" bun:"rel:belongs-to,join:user_id=id"`
 	Summary   *Summary   `json:"summary,omitempty" bun:"rel:has-one,join:id=chat_id"`
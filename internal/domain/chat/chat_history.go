@@ -9,31 +9,42 @@ import (
 type History struct {
 	bun.BaseModel `bun:"table:chat_histories,alias:ch"`
 
-	ID         int64     `json:"id" db:"id" bun:"id,pk,autoincrement"`
-	Role       string    `json:"role" db:"role" bun:"role"`
-	Content    string    `json:"content" db:"content" bun:"content"`
-	Metadata   []byte    `json:"metadata" db:"metadata" bun:"metadata,type:json"`
-	MessageID  string    `json:"message_id" db:"message_id" bun:"message_id"`
-	InsertedAt time.Time `json:"inserted_at" db:"inserted_at" bun:"inserted_at,notnull,default:CURRENT_TIMESTAMP"`
-	ChatID     int64     `json:"chat_id" db:"chat_id" bun:"chat_id,notnull"`
+	ID          int64        `json:"id" db:"id" bun:"id,pk,autoincrement"`
+	Role        string       `json:"role" db:"role" bun:"role"`
+	Content     string       `json:"content" db:"content" bun:"content"`
+	Metadata    []byte       `json:"metadata" db:"metadata" bun:"metadata,type:json"`
+	MessageID   string       `json:"message_id" db:"message_id" bun:"message_id"`
+	CancelledAt bun.NullTime `json:"cancelled_at" db:"cancelled_at" bun:"cancelled_at"`
+	Sequence    int64        `json:"sequence" db:"sequence" bun:"sequence"`
+	InsertedAt  time.Time    `json:"inserted_at" db:"inserted_at" bun:"inserted_at,notnull,default:CURRENT_TIMESTAMP"`
+	ChatID      int64        `json:"chat_id" db:"chat_id" bun:"chat_id,notnull"`
 
 	Chat *Chat `json:"chat,omitempty" bun:"rel:belongs-to,join:chat_id=id"`
 }
 
+// ActiveHistories leaves out turns that were cancelled or discarded while being generated.
+func ActiveHistories(q *bun.SelectQuery) *bun.SelectQuery {
+	return q.Where("cancelled_at IS NULL")
+}
+
 type HistoryDTO struct {
-	ID       string    `json:"id"`
-	When     time.Time `json:"when"`
-	Role     string    `json:"role"`
-	Content  string    `json:"content"`
-	Metadata []byte    `json:"metadata"`
+	ID          string    `json:"id"`
+	Sequence    int64     `json:"sequence"`
+	When        time.Time `json:"when"`
+	Role        string    `json:"role"`
+	Content     string    `json:"content"`
+	Metadata    []byte    `json:"metadata"`
+	IsCancelled bool      `json:"is_cancelled"`
 }
 
 func (h *History) ToHistoryDTO() HistoryDTO {
 	return HistoryDTO{
-		ID:       h.MessageID,
-		When:     h.InsertedAt,
-		Role:     h.Role,
-		Content:  h.Content,
-		Metadata: h.Metadata,
+		ID:          h.MessageID,
+		Sequence:    h.Sequence,
+		When:        h.InsertedAt,
+		Role:        h.Role,
+		Content:     h.Content,
+		Metadata:    h.Metadata,
+		IsCancelled: !h.CancelledAt.IsZero(),
 	}
 }
@@ -0,0 +1,7 @@
+package chat
+
+// Notify payloads pushed to chat sessions from outside the chat websocket handler.
+const (
+	NotifyConversationFinished = "conversation_finished"
+	NotifySummaryReady         = "summary_ready"
+)
@@ -7,17 +7,19 @@ import (
 	"github.com/solutionchallenge/ondaum-server/pkg/jwt"
 	"github.com/solutionchallenge/ondaum-server/pkg/llm"
 	"github.com/solutionchallenge/ondaum-server/pkg/oauth"
+	"github.com/solutionchallenge/ondaum-server/pkg/websocket"
 )
 
 type AppConfig struct {
-	Verbose        bool            `mapstructure:"verbose"`
-	HttpConfig     http.Config     `mapstructure:"http"`
-	DatabaseConfig database.Config `mapstructure:"database"`
-	Migration      MigrationConfig `mapstructure:"migration"`
-	OAuthConfig    oauth.Config    `mapstructure:"oauth"`
-	JWTConfig      jwt.Config      `mapstructure:"jwt"`
-	FutureConfig   future.Config   `mapstructure:"future"`
-	LLMConfig      llm.Config      `mapstructure:"llm"`
+	Verbose         bool             `mapstructure:"verbose"`
+	HttpConfig      http.Config      `mapstructure:"http"`
+	DatabaseConfig  database.Config  `mapstructure:"database"`
+	Migration       MigrationConfig  `mapstructure:"migration"`
+	OAuthConfig     oauth.Config     `mapstructure:"oauth"`
+	JWTConfig       jwt.Config       `mapstructure:"jwt"`
+	FutureConfig    future.Config    `mapstructure:"future"`
+	LLMConfig       llm.Config       `mapstructure:"llm"`
+	WebsocketConfig websocket.Config `mapstructure:"websocket"`
 }
 
 type MigrationConfig struct {
@@ -19,6 +19,20 @@ var PredefinedRoutes = []fx.Option{
 	dependency.HttpRoute("GET", "/_sys/migrations", sys.NewGetMigrationsHandler),
 	dependency.HttpRoute("GET", "/_sys/health", sys.NewGetHealthHandler),
 	dependency.HttpRoute("GET", "/_sys/tokens", sys.NewGetTokensHandler),
+	dependency.HttpRoute("GET", "/_sys/connections", sys.NewGetConnectionsHandler),
+	dependency.HttpRoute("GET", "/_sys/jobs", sys.NewListJobsHandler),
+	dependency.AdminHttpRoute("DELETE", "/_sys/jobs", sys.NewPurgeJobsHandler),
+	dependency.HttpRoute("GET", "/_sys/jobs/:job_id", sys.NewGetJobHandler),
+	dependency.AdminHttpRoute("POST", "/_sys/jobs/:job_id/cancel", sys.NewCancelJobHandler),
+	dependency.AdminHttpRoute("POST", "/_sys/jobs/:job_id/retry", sys.NewRetryJobHandler),
+	dependency.HttpRoute("GET", "/_sys/dead_letters", sys.NewListDeadLettersHandler),
+	dependency.AdminHttpRoute("POST", "/_sys/dead_letters/replay", sys.NewReplayDeadLettersHandler),
+	dependency.AdminHttpRoute("POST", "/_sys/dead_letters/:job_id/replay", sys.NewReplayDeadLetterHandler),
+	dependency.HttpRoute("GET", "/_sys/alerts", sys.NewGetAlertsHandler),
+	dependency.HttpRoute("GET", "/_sys/schedules", sys.NewListSchedulesHandler),
+	dependency.AdminHttpRoute("POST", "/_sys/schedules/:name/enable", sys.NewEnableScheduleHandler),
+	dependency.AdminHttpRoute("POST", "/_sys/schedules/:name/disable", sys.NewDisableScheduleHandler),
+	dependency.AdminHttpRoute("DELETE", "/_sys/schedules/:name", sys.NewDeleteScheduleHandler),
 	dependency.HttpRoute("POST", "/_debug/user", debug.NewUpsertUserHandler),
 	dependency.HttpRoute("POST", "/_debug/auth", debug.NewAuthUserHandler),
 	dependency.HttpRoute("GET", "/_debug/oauth", debug.NewOAuthCallbackHandler),
@@ -34,6 +48,8 @@ var PredefinedRoutes = []fx.Option{
 	dependency.HttpRoute("GET", "/chats/:session_id", chat.NewGetChatHandler),
 	dependency.HttpRoute("PUT", "/chats/:session_id/summary", chat.NewUpsertChatSummaryHandler),
 	dependency.HttpRoute("POST", "/chats/:session_id/archive", chat.NewArchiveChatHandler),
+	dependency.HttpRoute("POST", "/chats/:session_id/messages", chat.NewPostChatMessageHandler),
+	dependency.HttpRoute("GET", "/chats/:session_id/events", chat.NewGetChatEventsHandler),
 	dependency.HttpRoute("GET", "/chat-reports", chat.NewGetChatReportHandler),
 	dependency.HttpRoute("GET", "/diagnoses", diagnosis.NewListDiagnosisResultHandler),
 	dependency.HttpRoute("POST", "/diagnoses", diagnosis.NewReportDiagnosisResultHandler),
@@ -11,6 +11,7 @@ import (
 
 	"github.com/benbjohnson/clock"
 	"github.com/solutionchallenge/ondaum-server/internal/dependency"
+	chathandler "github.com/solutionchallenge/ondaum-server/internal/handler/chat"
 	"github.com/solutionchallenge/ondaum-server/migration"
 	"github.com/solutionchallenge/ondaum-server/pkg/database"
 	"github.com/solutionchallenge/ondaum-server/pkg/http"
@@ -37,14 +38,17 @@ func Run(config AppConfig) {
 		fx.Supply(config.JWTConfig),
 		fx.Supply(config.FutureConfig),
 		fx.Supply(config.LLMConfig),
+		fx.Supply(config.WebsocketConfig),
 		dependency.NewDatabaseModule(config.DatabaseConfig, utils.DebugLevel),
 		dependency.ProvideMiddleware(http.NewJWTAuthMiddleware),
 		dependency.NewHttpModule("/api/v1", PredefinedRoutes...),
 		dependency.NewOAuthModule(config.OAuthConfig),
-		dependency.NewWebsocketModule(WebsocketRoutes...),
+		dependency.NewWebsocketModule(config.WebsocketConfig, WebsocketRoutes...),
 		dependency.NewFutureModule(config.FutureConfig, FutureProcesses...),
 		dependency.NewLLMModule(config.LLMConfig),
 		fx.Provide(jwt.NewGenerator),
+		fx.Provide(chathandler.NewGenerationRegistry),
+		fx.Provide(chathandler.NewService),
 		fx.Invoke(func(db *sql.DB) {
 			if config.Migration.Enabled {
 				ctx := context.Background()
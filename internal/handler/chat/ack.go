@@ -0,0 +1,26 @@
+package chat
+
+import (
+	"context"
+
+	domain "github.com/solutionchallenge/ondaum-server/internal/domain/chat"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
+	"github.com/uptrace/bun"
+)
+
+func HandleAck(db *bun.DB, request wspkg.MessageWrapper, sequence SequencePayload) (wspkg.ResponseWrapper, bool, error) {
+	_, err := db.NewUpdate().
+		Model((*domain.Chat)(nil)).
+		Set("acked_sequence = ?", int64(sequence)).
+		Where("session_id = ?", request.SessionID).
+		Where("user_id = ?", request.UserID).
+		Where("acked_sequence < ?", int64(sequence)).
+		Exec(context.Background())
+	if err != nil {
+		utils.Log(utils.ErrorLevel).CID(request.SessionID).RID(request.MessageID).Err(err).BT().Send("Failed to update acked sequence")
+		return wspkg.ResponseWrapper{}, false, utils.WrapError(err, "failed to update acked sequence")
+	}
+
+	return wspkg.BuildNoopResponse(request), false, nil
+}
@@ -0,0 +1,23 @@
+package chat
+
+import (
+	"github.com/google/uuid"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
+)
+
+func HandleCancel(generations *GenerationRegistry, request wspkg.MessageWrapper) (wspkg.ResponseWrapper, bool, error) {
+	generation, ok := generations.Cancel(request.SessionID)
+	if !ok {
+		utils.Log(utils.InfoLevel).CID(request.SessionID).RID(request.MessageID).BT().Send("No in-flight generation to cancel")
+		return wspkg.BuildResponseFrom(
+			request, uuid.New().String(),
+			wspkg.PredefinedActionNotify, ChatPayloadNotifyNothingToCancel,
+		), false, nil
+	}
+	utils.Log(utils.InfoLevel).CID(request.SessionID).RID(request.MessageID).BT().Send("Cancelled generation for message %v", generation.MessageID)
+	return wspkg.BuildResponseFrom(
+		request, uuid.New().String(),
+		wspkg.PredefinedActionNotify, ChatPayloadNotifyGenerationCancelled,
+	), false, nil
+}
@@ -0,0 +1,19 @@
+package chat
+
+import (
+	"github.com/solutionchallenge/ondaum-server/pkg/llm"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
+)
+
+func HandleClose(hub *wspkg.Hub, request wspkg.CloseWrapper, llm llm.Client) {
+	// Websocket and event stream connections of a session share its conversation, so only the last one closes it.
+	if remaining := hub.SessionConnections(request.SessionID); remaining > 0 {
+		utils.Log(utils.DebugLevel).CID(request.SessionID).BT().Send("Keeping conversation for %d remaining connections", remaining)
+		return
+	}
+	err := llm.Close(request.SessionID)
+	if err != nil {
+		utils.Log(utils.ErrorLevel).Err(err).CID(request.SessionID).BT().Send("Failed to close conversation")
+	}
+}
@@ -19,15 +19,7 @@ func HandleConnect(db *bun.DB, clk clock.Clock, request wspkg.ConnectWrapper) (w
 		return wspkg.BuildRejectResponse(request), "", nil
 	}
 
-	chat := &domain.Chat{}
-	err := db.NewSelect().
-		Model(chat).
-		Where("user_id = ?", request.UserID).
-		Where("archived_at IS NULL").
-		Order("created_at DESC").
-		Limit(1).
-		Scan(context.Background())
-
+	chat, err := FindCurrentChat(context.Background(), db, request.UserID)
 	if err != nil {
 		if !errors.Is(err, sql.ErrNoRows) {
 			utils.Log(utils.ErrorLevel).CID(request.ConnectID).Err(err).BT().Send("Failed to get chat")
@@ -61,3 +53,19 @@ func HandleConnect(db *bun.DB, clk clock.Clock, request wspkg.ConnectWrapper) (w
 		wspkg.PredefinedActionNotify, ChatPayloadNotifyExistingConversation,
 	), request.ConnectID, nil
 }
+
+// FindCurrentChat returns the conversation every transport connects to: the user's most recent non-archived chat.
+func FindCurrentChat(ctx context.Context, db *bun.DB, userID int64) (*domain.Chat, error) {
+	chat := &domain.Chat{}
+	err := db.NewSelect().
+		Model(chat).
+		Where("user_id = ?", userID).
+		Where("archived_at IS NULL").
+		Order("created_at DESC").
+		Limit(1).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return chat, nil
+}
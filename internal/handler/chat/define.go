@@ -0,0 +1,35 @@
+package chat
+
+import (
+	"time"
+
+	domain "github.com/solutionchallenge/ondaum-server/internal/domain/chat"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
+)
+
+const (
+	ChatAutoFinishAfter = 30 * time.Minute
+)
+
+const (
+	ChatActionChat   = wspkg.Action("chat")
+	ChatActionPing   = wspkg.Action("ping")
+	ChatActionAck    = wspkg.Action("ack")
+	ChatActionResume = wspkg.Action("resume")
+	ChatActionCancel = wspkg.Action("cancel")
+	ChatActionStatus = wspkg.Action("status")
+
+	ChatActionTypingStart = wspkg.Action("typing_start")
+	ChatActionTypingStop  = wspkg.Action("typing_stop")
+)
+
+const (
+	ChatPayloadNotifyConversationFinished = domain.NotifyConversationFinished
+	ChatPayloadNotifyConversationArchived = "conversation_archived"
+	ChatPayloadNotifyNewConversation      = "new_conversation"
+	ChatPayloadNotifyExistingConversation = "existing_conversation"
+	ChatPayloadNotifySummaryReady         = domain.NotifySummaryReady
+	ChatPayloadNotifyConversationResumed  = "conversation_resumed"
+	ChatPayloadNotifyGenerationCancelled  = "generation_cancelled"
+	ChatPayloadNotifyNothingToCancel      = "nothing_to_cancel"
+)
@@ -0,0 +1,84 @@
+package chat
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Generation struct {
+	SessionID string
+	MessageID string
+	Cancel    context.CancelFunc
+	Cancelled atomic.Bool
+}
+
+type GenerationRegistry struct {
+	Generations map[string]*Generation
+	CancelledAt map[string]time.Time
+	Mutex       sync.Mutex
+}
+
+func NewGenerationRegistry() *GenerationRegistry {
+	return &GenerationRegistry{
+		Generations: make(map[string]*Generation),
+		CancelledAt: make(map[string]time.Time),
+	}
+}
+
+func (r *GenerationRegistry) Begin(ctx context.Context, sessionID string, messageID string) (context.Context, *Generation, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	generation := &Generation{
+		SessionID: sessionID,
+		MessageID: messageID,
+		Cancel:    cancel,
+	}
+	r.Mutex.Lock()
+	r.Generations[sessionID] = generation
+	r.Mutex.Unlock()
+	return ctx, generation, func() {
+		r.Mutex.Lock()
+		if r.Generations[sessionID] == generation {
+			delete(r.Generations, sessionID)
+		}
+		r.Mutex.Unlock()
+		cancel()
+	}
+}
+
+func (r *GenerationRegistry) Cancel(sessionID string) (*Generation, bool) {
+	r.Mutex.Lock()
+	r.CancelledAt[sessionID] = time.Now()
+	generation, ok := r.Generations[sessionID]
+	if ok {
+		delete(r.Generations, sessionID)
+	}
+	r.Mutex.Unlock()
+	if !ok {
+		return nil, false
+	}
+	generation.Cancelled.Store(true)
+	generation.Cancel()
+	return generation, true
+}
+
+// Dropped reports whether a turn received at receivedAt was still queued when
+// the session was cancelled. Session workers run turns in arrival order, so the
+// cancel mark is cleared by the first turn received after it.
+func (r *GenerationRegistry) Dropped(sessionID string, receivedAt time.Time) bool {
+	if receivedAt.IsZero() {
+		return false
+	}
+	r.Mutex.Lock()
+	defer r.Mutex.Unlock()
+	cancelledAt, ok := r.CancelledAt[sessionID]
+	if !ok {
+		return false
+	}
+	if receivedAt.Before(cancelledAt) {
+		return true
+	}
+	delete(r.CancelledAt, sessionID)
+	return false
+}
@@ -0,0 +1,73 @@
+package chat
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+var testcases_GenerationRegistry_Dropped = []struct {
+	name     string
+	cancel   bool
+	offset   time.Duration
+	expected bool
+}{
+	{
+		name:     "Success Case - No Cancel",
+		offset:   -time.Second,
+		expected: false,
+	},
+	{
+		name:     "Success Case - Turn Queued Before Cancel",
+		cancel:   true,
+		offset:   -time.Second,
+		expected: true,
+	},
+	{
+		name:     "Success Case - Turn Received After Cancel",
+		cancel:   true,
+		offset:   time.Second,
+		expected: false,
+	},
+}
+
+func Test_GenerationRegistry_Dropped(t *testing.T) {
+	for _, testcase := range testcases_GenerationRegistry_Dropped {
+		t.Run(testcase.name, func(t *testing.T) {
+			registry := NewGenerationRegistry()
+			if testcase.cancel {
+				registry.Cancel("session")
+			}
+			receivedAt := time.Now().Add(testcase.offset)
+			if dropped := registry.Dropped("session", receivedAt); dropped != testcase.expected {
+				t.Fatalf("expected dropped %v, got %v", testcase.expected, dropped)
+			}
+			if registry.Dropped("session", time.Time{}) {
+				t.Fatalf("expected turn without receive time to run")
+			}
+		})
+	}
+}
+
+func Test_GenerationRegistry_CancelRunning(t *testing.T) {
+	registry := NewGenerationRegistry()
+	ctx, _, done := registry.Begin(context.Background(), "session", "message")
+	defer done()
+
+	generation, ok := registry.Cancel("session")
+	if !ok || generation.MessageID != "message" {
+		t.Fatalf("expected running generation to be cancelled")
+	}
+	if ctx.Err() == nil {
+		t.Fatalf("expected generation context to be cancelled")
+	}
+	if !registry.Dropped("session", time.Now().Add(-time.Second)) {
+		t.Fatalf("expected queued turn to be dropped")
+	}
+	if registry.Dropped("session", time.Now().Add(time.Second)) {
+		t.Fatalf("expected later turn to run")
+	}
+	if _, ok := registry.CancelledAt["session"]; ok {
+		t.Fatalf("expected cancel mark to be cleared")
+	}
+}
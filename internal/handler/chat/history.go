@@ -16,6 +16,7 @@ type ChatHistoryManager struct {
 	db             *bun.DB
 	memoryCache    []llm.Message
 	conversationID string
+	sequences      map[string]int64
 }
 
 func NewChatHistoryManager(db *bun.DB, conversationID string) *ChatHistoryManager {
@@ -23,18 +24,34 @@ func NewChatHistoryManager(db *bun.DB, conversationID string) *ChatHistoryManage
 		db:             db,
 		memoryCache:    []llm.Message{},
 		conversationID: conversationID,
+		sequences:      map[string]int64{},
 	}
 }
 
-func (h *ChatHistoryManager) Add(ctx context.Context, messages ...llm.Message) {
+func (h *ChatHistoryManager) Add(ctx context.Context, messages ...llm.Message) error {
 	h.memoryCache = append(h.memoryCache, messages...)
-	h.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+	return h.db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		// Locking the chat row serializes sequence allocation across replicas and transports.
 		chat := domain.Chat{}
-		err := tx.NewSelect().Model(&chat).Where("session_id = ?", h.conversationID).Scan(ctx)
-		if err != nil || chat.ID == 0 {
+		err := tx.NewSelect().Model(&chat).Where("session_id = ?", h.conversationID).For("UPDATE").Scan(ctx)
+		if err != nil {
 			utils.Log(utils.WarnLevel).CID(h.conversationID).Err(err).BT().Send("Failed to query chat")
 			return utils.WrapError(err, "failed to query chat")
 		}
+		if chat.ID == 0 {
+			return utils.NewError("chat not found for session_id: %v", h.conversationID)
+		}
+
+		var lastSequence int64
+		err = tx.NewSelect().
+			Model((*domain.History)(nil)).
+			ColumnExpr("COALESCE(MAX(sequence), 0)").
+			Where("chat_id = ?", chat.ID).
+			Scan(ctx, &lastSequence)
+		if err != nil {
+			utils.Log(utils.WarnLevel).CID(h.conversationID).Err(err).BT().Send("Failed to query last sequence")
+			return utils.WrapError(err, "failed to query last sequence")
+		}
 
 		histories := make([]domain.History, 0, len(messages))
 		for _, message := range messages {
@@ -43,9 +60,11 @@ func (h *ChatHistoryManager) Add(ctx context.Context, messages ...llm.Message) {
 				utils.Log(utils.WarnLevel).CID(h.conversationID).Err(err).BT().Send("Failed to marshal metadata")
 				continue
 			}
+			lastSequence++
 			histories = append(histories, domain.History{
 				ChatID:    chat.ID,
 				MessageID: message.ID,
+				Sequence:  lastSequence,
 				Role:      string(message.Role),
 				Content:   message.Content,
 				Metadata:  marshaled,
@@ -58,17 +77,43 @@ func (h *ChatHistoryManager) Add(ctx context.Context, messages ...llm.Message) {
 				utils.Log(utils.WarnLevel).CID(h.conversationID).Err(err).BT().Send("Failed to bulk insert chat history")
 				return utils.WrapError(err, "failed to bulk insert chat history")
 			}
+			for _, history := range histories {
+				h.sequences[history.MessageID] = history.Sequence
+			}
 		}
 		return nil
 	})
 }
 
+func (h *ChatHistoryManager) SequenceOf(messageID string) int64 {
+	return h.sequences[messageID]
+}
+
+func (h *ChatHistoryManager) Cancel(ctx context.Context, messageIDs ...string) error {
+	if len(messageIDs) == 0 {
+		return nil
+	}
+	_, err := h.db.NewUpdate().
+		Model((*domain.History)(nil)).
+		Set("cancelled_at = CURRENT_TIMESTAMP").
+		Where("message_id IN (?)", bun.In(messageIDs)).
+		Where("chat_id = (?)", h.db.NewSelect().
+			Model((*domain.Chat)(nil)).
+			Column("id").
+			Where("session_id = ?", h.conversationID)).
+		Exec(ctx)
+	if err != nil {
+		return utils.WrapError(err, "failed to mark histories as cancelled")
+	}
+	return nil
+}
+
 func (h *ChatHistoryManager) Get(ctx context.Context, conversationID string) []llm.Message {
 	chat := &domain.Chat{}
 	err := h.db.NewSelect().
 		Model(chat).
 		Relation("Histories", func(q *bun.SelectQuery) *bun.SelectQuery {
-			return q.Order("inserted_at ASC")
+			return domain.ActiveHistories(q).Order("sequence ASC")
 		}).
 		Relation("Summary").
 		Where("session_id = ?", conversationID).
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/benbjohnson/clock"
+	"github.com/google/uuid"
 	domain "github.com/solutionchallenge/ondaum-server/internal/domain/chat"
 	"github.com/solutionchallenge/ondaum-server/internal/domain/common"
 	"github.com/solutionchallenge/ondaum-server/internal/domain/user"
@@ -20,31 +21,17 @@ import (
 )
 
 func HandleMessage(
-	db *bun.DB, clk clock.Clock, llm llm.Client, future *ftpkg.Scheduler, request wspkg.MessageWrapper,
+	db *bun.DB, clk clock.Clock, llm llm.Client, future *ftpkg.Scheduler, generations *GenerationRegistry,
+	report StatusReporter, request wspkg.MessageWrapper, payload ChatPayload,
 ) (wspkg.ResponseWrapper, bool, error) {
-	if !request.Authorized || !checkAuthorization(db, request.UserID) {
-		utils.Log(utils.InfoLevel).CID(request.SessionID).RID(request.MessageID).BT().Send("Unauthorized")
-		return wspkg.BuildRejectResponse(request), false, nil
-	}
-
-	if request.Action != wspkg.Action("chat") {
-		if request.Action == wspkg.Action("ping") {
-			return wspkg.BuildNoopResponse(request), false, nil
-		}
-		utils.Log(utils.ErrorLevel).CID(request.SessionID).RID(request.MessageID).BT().Send("Invalid action %v", request.Action)
-		return wspkg.ResponseWrapper{}, false, utils.WrapError(errors.New("invalid action"), "invalid action %v", request.Action)
-	}
-
-	if request.Payload == nil {
-		utils.Log(utils.ErrorLevel).CID(request.SessionID).RID(request.MessageID).BT().Send("Payload is nil")
-		return wspkg.ResponseWrapper{}, false, utils.WrapError(errors.New("payload is nil"), "payload is nil")
-	}
-
-	payload, ok := request.Payload.(string)
-	if !ok || payload == "" {
-		utils.Log(utils.ErrorLevel).CID(request.SessionID).RID(request.MessageID).BT().Send("Payload is empty")
-		return wspkg.ResponseWrapper{}, false, utils.WrapError(errors.New("payload is empty"), "payload is empty")
+	if generations.Dropped(request.SessionID, request.ReceivedAt) {
+		utils.Log(utils.InfoLevel).CID(request.SessionID).RID(request.MessageID).BT().Send("Dropped chat turn queued before cancel")
+		return wspkg.BuildResponseFrom(
+			request, uuid.New().String(),
+			wspkg.PredefinedActionNotify, ChatPayloadNotifyGenerationCancelled,
+		), false, nil
 	}
+	report(ChatStatusReceived)
 
 	var response wspkg.ResponseWrapper
 	var shouldClose bool
@@ -101,6 +88,9 @@ func HandleMessage(
 		utils.Log(utils.ErrorLevel).CID(request.SessionID).RID(request.MessageID).Err(err).BT().Send("Failed to run transaction")
 		return wspkg.ResponseWrapper{}, false, utils.WrapError(err, "failed to run transaction")
 	}
+	if response.Action != "" {
+		return response, shouldClose, nil
+	}
 
 	_, err = UpsertChattingEndFutureJob(context.Background(), future, request.SessionID, request.UserID, ChatAutoFinishAfter)
 	if err != nil {
@@ -108,20 +98,36 @@ func HandleMessage(
 		return wspkg.ResponseWrapper{}, false, utils.WrapError(err, "failed to upsert future job")
 	}
 
+	generationCtx, generation, endGeneration := generations.Begin(context.Background(), request.SessionID, request.MessageID)
+	defer endGeneration()
+
 	manager := NewChatHistoryManager(db, request.SessionID)
-	conversation, err := llm.StartConversation(context.Background(), manager, "interactive_chat", request.SessionID)
+	report(ChatStatusGenerating)
+	conversation, err := llm.StartConversation(generationCtx, manager, "interactive_chat", request.SessionID)
 	if err != nil {
 		utils.Log(utils.ErrorLevel).CID(request.SessionID).RID(request.MessageID).Err(err).BT().Send("Failed to start conversation")
 		return wspkg.ResponseWrapper{}, false, utils.WrapError(err, "failed to start conversation")
 	}
 	utils.Log(utils.DebugLevel).CID(request.SessionID).RID(request.MessageID).BT().Send("Message Request: %v", payload)
-	llmResponse, err := conversation.Request(context.Background(), llmpkg.Message{
+	llmResponse, err := conversation.Request(generationCtx, llmpkg.Message{
 		ConversationID: request.SessionID,
 		ID:             request.MessageID,
 		Role:           llmpkg.RoleUser,
 		Content:        payload,
 	})
+	if generation.Cancelled.Load() {
+		utils.Log(utils.InfoLevel).CID(request.SessionID).RID(request.MessageID).Err(err).BT().Send("Generation cancelled by client")
+		cancelled := []string{request.MessageID}
+		if llmResponse.ID != "" {
+			cancelled = append(cancelled, llmResponse.ID)
+		}
+		if err := manager.Cancel(context.Background(), cancelled...); err != nil {
+			utils.Log(utils.ErrorLevel).CID(request.SessionID).RID(request.MessageID).Err(err).BT().Send("Failed to mark cancelled histories")
+		}
+		return wspkg.BuildNoopResponse(request), false, nil
+	}
 	if err != nil || !IsValidChatLLMResponse(llmResponse.Content) {
+		report(ChatStatusFinalizing)
 		if errors.Is(err, gemini.PromptBlockedErr) || errors.Is(err, gemini.ContentBlockedErr) {
 			utils.Log(utils.InfoLevel).CID(request.SessionID).RID(request.MessageID).Err(err).BT().Send("Blocked by gemini")
 			marshaled, err := json.Marshal(ChatLLMResponse{
@@ -155,6 +161,7 @@ func HandleMessage(
 		utils.Log(utils.ErrorLevel).CID(request.SessionID).RID(request.MessageID).Err(err).BT().Send("Failed to request to conversation")
 		return wspkg.ResponseWrapper{}, false, utils.WrapError(err, "failed to request to conversation")
 	}
+	report(ChatStatusFinalizing)
 	marshaled, _ := json.Marshal(llmResponse.Metadata)
 	utils.Log(utils.DebugLevel).CID(request.SessionID).RID(request.MessageID).BT().Send("Message[%v] Response: %v", llmResponse.ID, llmResponse.Content)
 	utils.Log(utils.DebugLevel).CID(request.SessionID).RID(request.MessageID).BT().Send("Message[%v] Metadata: %v", llmResponse.ID, string(marshaled))
@@ -163,6 +170,7 @@ func HandleMessage(
 		request, llmResponse.ID,
 		wspkg.PredefinedActionData, llmResponse.Content,
 	)
+	response.Sequence = manager.SequenceOf(llmResponse.ID)
 
 	return response, shouldClose, nil
 }
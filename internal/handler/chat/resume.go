@@ -0,0 +1,70 @@
+package chat
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	domain "github.com/solutionchallenge/ondaum-server/internal/domain/chat"
+	"github.com/solutionchallenge/ondaum-server/pkg/llm"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
+	"github.com/uptrace/bun"
+)
+
+func HandleResume(
+	db *bun.DB, respond Responder, request wspkg.MessageWrapper, sequence *SequencePayload,
+) (wspkg.ResponseWrapper, bool, error) {
+	chat := &domain.Chat{}
+	err := db.NewSelect().
+		Model(chat).
+		Where("session_id = ?", request.SessionID).
+		Where("user_id = ?", request.UserID).
+		Scan(context.Background())
+	if err != nil {
+		utils.Log(utils.ErrorLevel).CID(request.SessionID).RID(request.MessageID).Err(err).BT().Send("Failed to query chat")
+		return wspkg.ResponseWrapper{}, false, utils.WrapError(err, "failed to query chat")
+	}
+
+	if !chat.ArchivedAt.IsZero() {
+		return wspkg.BuildCloseResponse(
+			request, wspkg.PredefinedActionNotify, ChatPayloadNotifyConversationArchived,
+		), false, nil
+	}
+
+	lastSequence := chat.AckedSequence
+	if sequence != nil {
+		lastSequence = int64(*sequence)
+	}
+
+	histories := []*domain.History{}
+	err = db.NewSelect().
+		Model(&histories).
+		Where("chat_id = ?", chat.ID).
+		Where("role = ?", string(llm.RoleModel)).
+		Where("sequence > ?", lastSequence).
+		Where("cancelled_at IS NULL").
+		Order("sequence ASC").
+		Scan(context.Background())
+	if err != nil {
+		utils.Log(utils.ErrorLevel).CID(request.SessionID).RID(request.MessageID).Err(err).BT().Send("Failed to query missed histories")
+		return wspkg.ResponseWrapper{}, false, utils.WrapError(err, "failed to query missed histories")
+	}
+
+	for _, history := range histories {
+		replayed := wspkg.BuildResponseFrom(
+			request, history.MessageID,
+			wspkg.PredefinedActionData, history.Content,
+		)
+		replayed.Sequence = history.Sequence
+		if err := respond(replayed); err != nil {
+			utils.Log(utils.ErrorLevel).CID(request.SessionID).RID(request.MessageID).Err(err).BT().Send("Failed to replay message")
+			return wspkg.ResponseWrapper{}, false, utils.WrapError(err, "failed to replay message")
+		}
+	}
+	utils.Log(utils.DebugLevel).CID(request.SessionID).RID(request.MessageID).BT().Send("Replayed %d messages after sequence %d", len(histories), lastSequence)
+
+	return wspkg.BuildResponseFrom(
+		request, uuid.New().String(),
+		wspkg.PredefinedActionNotify, ChatPayloadNotifyConversationResumed,
+	), false, nil
+}
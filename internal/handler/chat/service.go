@@ -0,0 +1,110 @@
+package chat
+
+import (
+	"github.com/benbjohnson/clock"
+	fiberws "github.com/gofiber/websocket/v2"
+	ftpkg "github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/llm"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
+	"github.com/uptrace/bun"
+	"go.uber.org/fx"
+)
+
+type ServiceDependencies struct {
+	fx.In
+	Future *ftpkg.Scheduler
+	LLM    llm.Client
+	DB     *bun.DB
+	Clock  clock.Clock
+	Hub    *wspkg.Hub
+
+	Generations *GenerationRegistry
+}
+
+// Service routes chat actions for every transport; the websocket handler and the SSE endpoints share one instance.
+type Service struct {
+	deps   ServiceDependencies
+	router *wspkg.Router
+}
+
+func NewService(deps ServiceDependencies) (*Service, error) {
+	service := &Service{
+		deps:   deps,
+		router: wspkg.NewRouter(),
+	}
+	service.router.Use(service.authorize)
+	wspkg.Register(service.router, ChatActionChat, service.handleChat, ValidateChatPayload)
+	wspkg.RegisterImmediate(service.router, ChatActionPing, service.handlePing)
+	wspkg.RegisterImmediate(service.router, ChatActionAck, service.handleAck, ValidateSequencePayload)
+	wspkg.Register(service.router, ChatActionResume, service.handleResume, ValidateOptionalSequencePayload)
+	wspkg.RegisterImmediate(service.router, ChatActionCancel, service.handleCancel)
+	wspkg.RegisterImmediate(service.router, ChatActionTypingStart, service.handleTyping)
+	wspkg.RegisterImmediate(service.router, ChatActionTypingStop, service.handleTyping)
+	return service, nil
+}
+
+// HandleMessage takes a nil conn for transports without a websocket; responses then go through the hub.
+func (s *Service) HandleMessage(c *fiberws.Conn, request wspkg.MessageWrapper) (wspkg.ResponseWrapper, bool, error) {
+	return s.router.Dispatch(c, request)
+}
+
+func (s *Service) IsImmediateAction(action wspkg.Action) bool {
+	return s.router.IsImmediateAction(action)
+}
+
+func (s *Service) HandleConnect(request wspkg.ConnectWrapper) (wspkg.ResponseWrapper, string, error) {
+	return HandleConnect(s.deps.DB, s.deps.Clock, request)
+}
+
+func (s *Service) HandleClose(request wspkg.CloseWrapper) {
+	HandleClose(s.deps.Hub, request, s.deps.LLM)
+}
+
+func (s *Service) HandlePing(request wspkg.PingWrapper) (wspkg.ResponseWrapper, bool, error) {
+	return HandlePing(s.deps.DB, request)
+}
+
+func (s *Service) authorize(_ *fiberws.Conn, request wspkg.MessageWrapper) (wspkg.ResponseWrapper, bool) {
+	return Authorize(s.deps.DB, request)
+}
+
+func (s *Service) handleChat(c *fiberws.Conn, request wspkg.MessageWrapper, payload ChatPayload) (wspkg.ResponseWrapper, bool, error) {
+	report := NewStatusReporter(NewResponder(s.deps.Hub, c), s.deps.Clock, request)
+	return HandleMessage(s.deps.DB, s.deps.Clock, s.deps.LLM, s.deps.Future, s.deps.Generations, report, request, payload)
+}
+
+func (s *Service) handlePing(_ *fiberws.Conn, request wspkg.MessageWrapper, _ any) (wspkg.ResponseWrapper, bool, error) {
+	return wspkg.BuildNoopResponse(request), false, nil
+}
+
+func (s *Service) handleAck(_ *fiberws.Conn, request wspkg.MessageWrapper, payload SequencePayload) (wspkg.ResponseWrapper, bool, error) {
+	s.background(func() {
+		_, _, _ = HandleAck(s.deps.DB, request, payload)
+	})
+	return wspkg.BuildNoopResponse(request), false, nil
+}
+
+func (s *Service) handleResume(c *fiberws.Conn, request wspkg.MessageWrapper, payload *SequencePayload) (wspkg.ResponseWrapper, bool, error) {
+	return HandleResume(s.deps.DB, NewResponder(s.deps.Hub, c), request, payload)
+}
+
+func (s *Service) handleCancel(_ *fiberws.Conn, request wspkg.MessageWrapper, _ any) (wspkg.ResponseWrapper, bool, error) {
+	return HandleCancel(s.deps.Generations, request)
+}
+
+func (s *Service) handleTyping(_ *fiberws.Conn, request wspkg.MessageWrapper, _ any) (wspkg.ResponseWrapper, bool, error) {
+	s.background(func() {
+		_, _, _ = HandleTyping(s.deps.Future, request)
+	})
+	return wspkg.BuildNoopResponse(request), false, nil
+}
+
+// background keeps database work of immediate actions off the read loop, so a
+// slow write never delays the frames behind it; failures are logged by the job.
+func (s *Service) background(job func()) {
+	s.deps.Hub.InFlightMessages.Add(1)
+	go func() {
+		defer s.deps.Hub.InFlightMessages.Add(-1)
+		job()
+	}()
+}
@@ -0,0 +1,42 @@
+package chat
+
+import (
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/google/uuid"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
+)
+
+type ChatStatus string
+
+const (
+	ChatStatusReceived   ChatStatus = "received"
+	ChatStatusGenerating ChatStatus = "generating"
+	ChatStatusFinalizing ChatStatus = "finalizing"
+)
+
+type ChatStatusPayload struct {
+	Status    ChatStatus `json:"status"`
+	RequestID string     `json:"request_id"`
+	At        time.Time  `json:"at"`
+}
+
+type StatusReporter func(status ChatStatus)
+
+func NewStatusReporter(respond Responder, clk clock.Clock, request wspkg.MessageWrapper) StatusReporter {
+	return func(status ChatStatus) {
+		response := wspkg.BuildResponseFrom(
+			request, uuid.New().String(),
+			ChatActionStatus, ChatStatusPayload{
+				Status:    status,
+				RequestID: request.MessageID,
+				At:        clk.Now().UTC(),
+			},
+		)
+		if err := respond(response); err != nil {
+			utils.Log(utils.WarnLevel).CID(request.SessionID).RID(request.MessageID).Err(err).BT().Send("Failed to send %v status", status)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package chat
 import (
 	"encoding/json"
 	"slices"
+	"strconv"
 
 	"github.com/solutionchallenge/ondaum-server/internal/domain/common"
 	"github.com/solutionchallenge/ondaum-server/pkg/utils"
@@ -48,3 +49,48 @@ func IsValidChatLLMResponse(response string) bool {
 	_, err := ParseChatLLMResponse(response)
 	return err == nil
 }
+
+type ChatPayload = string
+
+func ValidateChatPayload(payload ChatPayload) error {
+	if payload == "" {
+		return utils.NewError("payload is empty")
+	}
+	return nil
+}
+
+type SequencePayload int64
+
+func (p *SequencePayload) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return utils.WrapError(err, "invalid sequence")
+	}
+	switch value := value.(type) {
+	case float64:
+		*p = SequencePayload(value)
+	case string:
+		sequence, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return utils.WrapError(err, "invalid sequence %v", value)
+		}
+		*p = SequencePayload(sequence)
+	default:
+		return utils.NewError("invalid sequence type %T", value)
+	}
+	return nil
+}
+
+func ValidateSequencePayload(payload SequencePayload) error {
+	if payload < 0 {
+		return utils.NewError("sequence must not be negative")
+	}
+	return nil
+}
+
+func ValidateOptionalSequencePayload(payload *SequencePayload) error {
+	if payload == nil {
+		return nil
+	}
+	return ValidateSequencePayload(*payload)
+}
@@ -0,0 +1,27 @@
+package chat
+
+import (
+	"context"
+
+	ftpkg "github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
+)
+
+func HandleTyping(future *ftpkg.Scheduler, request wspkg.MessageWrapper) (wspkg.ResponseWrapper, bool, error) {
+	job, err := future.FindBy(context.Background(), request.SessionID)
+	if err != nil {
+		utils.Log(utils.ErrorLevel).CID(request.SessionID).RID(request.MessageID).Err(err).BT().Send("Failed to find future job")
+		return wspkg.ResponseWrapper{}, false, utils.WrapError(err, "failed to find future job")
+	}
+	if job == nil {
+		return wspkg.BuildNoopResponse(request), false, nil
+	}
+	err = future.Reschdule(context.Background(), job.ID, ChatAutoFinishAfter, true)
+	if err != nil {
+		utils.Log(utils.ErrorLevel).CID(request.SessionID).RID(request.MessageID).Err(err).BT().Send("Failed to reschedule future job")
+		return wspkg.ResponseWrapper{}, false, utils.WrapError(err, "failed to reschedule future job")
+	}
+	utils.Log(utils.DebugLevel).CID(request.SessionID).RID(request.MessageID).BT().Send("Postponed auto finish on %v", request.Action)
+	return wspkg.BuildNoopResponse(request), false, nil
+}
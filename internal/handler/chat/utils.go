@@ -0,0 +1,38 @@
+package chat
+
+import (
+	"context"
+
+	fiberws "github.com/gofiber/websocket/v2"
+	"github.com/solutionchallenge/ondaum-server/internal/domain/user"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
+	"github.com/uptrace/bun"
+)
+
+func checkAuthorization(db *bun.DB, userID int64) bool {
+	user := &user.User{ID: userID}
+	err := db.NewSelect().Model(user).Where("id = ?", userID).Scan(context.Background())
+	return err == nil
+}
+
+func Authorize(db *bun.DB, request wspkg.MessageWrapper) (wspkg.ResponseWrapper, bool) {
+	if !request.Authorized || !checkAuthorization(db, request.UserID) {
+		utils.Log(utils.InfoLevel).CID(request.SessionID).RID(request.MessageID).BT().Send("Unauthorized")
+		return wspkg.BuildRejectResponse(request), false
+	}
+	return wspkg.ResponseWrapper{}, true
+}
+
+type Responder func(response wspkg.ResponseWrapper) error
+
+func NewResponder(hub *wspkg.Hub, c *fiberws.Conn) Responder {
+	if c != nil {
+		if conn, err := hub.Lookup(c); err == nil {
+			return conn.WriteResponse
+		}
+	}
+	return func(response wspkg.ResponseWrapper) error {
+		return hub.PublishSession(response.SessionID, response)
+	}
+}
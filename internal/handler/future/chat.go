@@ -2,24 +2,30 @@ package future
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"time"
 
 	"github.com/benbjohnson/clock"
 	domain "github.com/solutionchallenge/ondaum-server/internal/domain/chat"
 	"github.com/solutionchallenge/ondaum-server/pkg/future"
 	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
 	"github.com/uptrace/bun"
 	"go.uber.org/fx"
 )
 
 const (
-	ChatJobType = future.JobType("chat")
+	ChatJobType    = future.JobType("chat")
+	ChatJobTimeout = 1 * time.Minute
 )
 
 type ChatFutureHandlerDependencies struct {
 	fx.In
 	DB    *bun.DB
 	Clock clock.Clock
+	Hub   *wspkg.Hub
 }
 
 type ChatFutureHandlerParams struct {
@@ -37,11 +43,15 @@ func NewChatFutureHandler(deps ChatFutureHandlerDependencies) (*ChatFutureHandle
 	}, nil
 }
 
+func (h *ChatFutureHandler) Timeout() time.Duration {
+	return ChatJobTimeout
+}
+
 func (h *ChatFutureHandler) Handle(ctx context.Context, job *future.Job) error {
 	var input ChatFutureHandlerParams
 	err := json.Unmarshal([]byte(job.ActionParams), &input)
 	if err != nil {
-		return utils.WrapError(err, "failed to unmarshal future job params")
+		return future.Permanent(utils.WrapError(err, "failed to unmarshal future job params"))
 	}
 
 	tx, err := h.deps.DB.BeginTx(ctx, nil)
@@ -57,10 +67,15 @@ func (h *ChatFutureHandler) Handle(ctx context.Context, job *future.Job) error {
 		Where("user_id = ?", input.UserID).
 		Scan(ctx)
 	if err != nil {
-		return utils.WrapError(err, "failed to select chat (%v:%v)", input.UserID, input.ConversationID)
+		err = utils.WrapError(err, "failed to select chat (%v:%v)", input.UserID, input.ConversationID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return future.Permanent(err)
+		}
+		return err
 	}
 
-	if chat.FinishedAt.IsZero() {
+	unfinished := chat.FinishedAt.IsZero()
+	if unfinished {
 		_, err = tx.NewUpdate().
 			Model(chat).
 			Set("finished_at = CURRENT_TIMESTAMP").
@@ -75,5 +90,14 @@ func (h *ChatFutureHandler) Handle(ctx context.Context, job *future.Job) error {
 		return utils.WrapError(err, "failed to commit transaction (%v:%v)", input.UserID, input.ConversationID)
 	}
 
+	if unfinished {
+		err = h.deps.Hub.PublishSession(input.ConversationID, wspkg.BuildPushResponse(
+			input.ConversationID, wspkg.PredefinedActionNotify, domain.NotifyConversationFinished,
+		))
+		if err != nil {
+			utils.Log(utils.WarnLevel).CID(input.ConversationID).Err(err).BT().Send("Failed to publish conversation finished")
+		}
+	}
+
 	return nil
 }
@@ -69,7 +69,7 @@ func (h *GetChatHandler) Handle(c *fiber.Ctx) error {
 	err = h.deps.DB.NewSelect().
 		Model(chat).
 		Relation("Histories", func(q *bun.SelectQuery) *bun.SelectQuery {
-			return q.Order("inserted_at ASC")
+			return domain.ActiveHistories(q).Order("sequence ASC")
 		}).
 		Relation("Summary").
 		Where("session_id = ?", sessionID).
@@ -0,0 +1,189 @@
+package chat
+
+import (
+	"bufio"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	chathandler "github.com/solutionchallenge/ondaum-server/internal/handler/chat"
+	"github.com/solutionchallenge/ondaum-server/pkg/http"
+	"github.com/solutionchallenge/ondaum-server/pkg/jwt"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
+	"github.com/uptrace/bun"
+	"go.uber.org/fx"
+)
+
+type GetChatEventsHandlerDependencies struct {
+	fx.In
+	DB   *bun.DB
+	Hub  *wspkg.Hub
+	JWT  jwt.Generator
+	Chat *chathandler.Service
+}
+
+type GetChatEventsHandler struct {
+	deps GetChatEventsHandlerDependencies
+}
+
+func NewGetChatEventsHandler(deps GetChatEventsHandlerDependencies) (*GetChatEventsHandler, error) {
+	return &GetChatEventsHandler{deps: deps}, nil
+}
+
+// @ID GetChatEvents
+// @Summary Stream chat events
+// @Description Server-Sent Events fallback for the chat websocket. Emits the same frames (notify, status, data, reject) as events named after their action, with the history sequence as the event id. Reconnect with Last-Event-ID to replay missed replies.
+// @Tags chat
+// @Produce text/event-stream
+// @Param session_id path string true "Session ID (the most recent non-archived conversation is used if one exists, like the websocket)"
+// @Param access_token query string false "Access Token (optional; for clients that cannot set the Authorization header)"
+// @Param Last-Event-ID header string false "Last received sequence to resume after"
+// @Success 200 {object} wspkg.ResponseWrapper
+// @Failure 400 {object} http.Error
+// @Failure 401 {object} http.Error
+// @Failure 500 {object} http.Error
+// @Failure 503 {object} http.Error
+// @Router /chats/{session_id}/events [get]
+// @Security BearerAuth
+func (h *GetChatEventsHandler) Handle(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	userID, userMetadata, expiry, err := h.authenticate(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			http.NewError(ctx, err, "Unauthorized"),
+		)
+	}
+
+	sessionID := strings.Clone(c.Params("session_id"))
+	if sessionID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			http.NewError(ctx, errors.New("session_id is required"), "Bad Request"),
+		)
+	}
+
+	var lastSequence *chathandler.SequencePayload
+	if lastEventID := c.Get("Last-Event-ID", c.Query("last_event_id")); lastEventID != "" {
+		parsed, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				http.NewError(ctx, err, "Invalid Last-Event-ID: %v", lastEventID),
+			)
+		}
+		sequence := chathandler.SequencePayload(parsed)
+		lastSequence = &sequence
+	}
+
+	if h.deps.Hub.Draining() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(
+			http.NewError(ctx, errors.New("server is restarting"), "Service Unavailable"),
+		)
+	}
+
+	connected, sessionID, err := h.deps.Chat.HandleConnect(wspkg.ConnectWrapper{
+		ConnectID:    sessionID,
+		Authorized:   true,
+		UserID:       userID,
+		UserMetadata: userMetadata,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to connect chat for session_id: %v", sessionID),
+		)
+	}
+	if slices.Contains(connected.ControlFlags, wspkg.ControlFlagClose) {
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			http.NewError(ctx, errors.New("connection rejected"), "Unauthorized"),
+		)
+	}
+
+	stream := wspkg.NewStreamConnection(sessionID, userID, h.deps.Hub.Config.Worker.OutboxSize)
+	stream.SetTokenExpiry(expiry)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		err := h.deps.Hub.ServeStream(stream, w, func() {
+			h.replay(stream, connected, lastSequence)
+		})
+		if err != nil {
+			utils.Log(utils.InfoLevel).CID(sessionID).Err(err).BT().Send("Event stream closed")
+		}
+		h.deps.Chat.HandleClose(wspkg.CloseWrapper{
+			SessionID:    sessionID,
+			Authorized:   true,
+			UserID:       userID,
+			UserMetadata: userMetadata,
+		})
+	})
+	return nil
+}
+
+func (h *GetChatEventsHandler) replay(stream *wspkg.Connection, connected wspkg.ResponseWrapper, lastSequence *chathandler.SequencePayload) {
+	if err := stream.WriteResponse(connected); err != nil {
+		utils.Log(utils.WarnLevel).CID(stream.SessionID).Err(err).BT().Send("Failed to send connect event")
+		return
+	}
+	if lastSequence == nil {
+		return
+	}
+	request := wspkg.MessageWrapper{
+		Action:     chathandler.ChatActionResume,
+		SessionID:  stream.SessionID,
+		MessageID:  uuid.New().String(),
+		Authorized: true,
+		UserID:     stream.UserID,
+	}
+	resumed, _, err := chathandler.HandleResume(h.deps.DB, stream.WriteResponse, request, lastSequence)
+	if err != nil {
+		utils.Log(utils.ErrorLevel).CID(stream.SessionID).RID(request.MessageID).Err(err).BT().Send("Failed to resume event stream")
+		return
+	}
+	if !slices.Contains(resumed.ControlFlags, wspkg.ControlFlagQuite) {
+		if err := stream.WriteResponse(resumed); err != nil {
+			utils.Log(utils.WarnLevel).CID(stream.SessionID).RID(request.MessageID).Err(err).BT().Send("Failed to send resume event")
+		}
+	}
+	if slices.Contains(resumed.ControlFlags, wspkg.ControlFlagClose) {
+		stream.Close()
+	}
+}
+
+func (h *GetChatEventsHandler) authenticate(c *fiber.Ctx) (int64, map[string]any, time.Time, error) {
+	if userID, err := http.GetUserID(c); err == nil {
+		userMetadata, _ := http.GetUserMetadata(c)
+		expiry, _ := http.GetTokenExpiry(c)
+		return userID, userMetadata, expiry, nil
+	}
+	token := c.Query("access_token")
+	if token == "" {
+		return 0, nil, time.Time{}, errors.New("access token is required")
+	}
+	claims, err := h.deps.JWT.UnpackToken(token)
+	if err != nil {
+		return 0, nil, time.Time{}, utils.WrapError(err, "failed to unpack access token")
+	}
+	if claims.Type != jwt.AccessTokenType {
+		return 0, nil, time.Time{}, errors.New("required valid access token")
+	}
+	userID, err := strconv.ParseInt(claims.Value, 10, 64)
+	if err != nil {
+		return 0, nil, time.Time{}, utils.WrapError(err, "invalid token subject")
+	}
+	expiry := time.Time{}
+	if claims.ExpiresAt != nil {
+		expiry = claims.ExpiresAt.Time
+	}
+	return userID, claims.Metadata, expiry, nil
+}
+
+func (h *GetChatEventsHandler) Identify() string {
+	return "get-chat-events"
+}
@@ -117,7 +117,8 @@ func (h *ListChatHandler) Handle(c *fiber.Ctx) error {
 		query = query.
 			Join("JOIN chat_histories ch").
 			JoinOn("ch.chat_id = c.id").
-			Where("ch.message_id = ?", messageID)
+			Where("ch.message_id = ?", messageID).
+			Where("ch.cancelled_at IS NULL")
 	}
 
 	var chats []domain.Chat
@@ -149,7 +150,8 @@ func (h *ListChatHandler) Handle(c *fiber.Ctx) error {
 			err := h.deps.DB.NewSelect().
 				Model((*domain.History)(nil)).
 				Where("chat_id = ?", chat.ID).
-				Order("created_at ASC").
+				Apply(domain.ActiveHistories).
+				Order("sequence ASC").
 				Scan(ctx, &histories)
 			if err == nil {
 				summaryDTO := chat.Summary.ToSummaryWithTopicMessages(histories)
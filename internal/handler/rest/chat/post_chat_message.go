@@ -0,0 +1,184 @@
+package chat
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	domain "github.com/solutionchallenge/ondaum-server/internal/domain/chat"
+	chathandler "github.com/solutionchallenge/ondaum-server/internal/handler/chat"
+	"github.com/solutionchallenge/ondaum-server/pkg/http"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
+	"github.com/uptrace/bun"
+	"go.uber.org/fx"
+)
+
+type PostChatMessageHandlerDependencies struct {
+	fx.In
+	DB   *bun.DB
+	Hub  *wspkg.Hub
+	Chat *chathandler.Service
+}
+
+type PostChatMessageHandler struct {
+	deps PostChatMessageHandlerDependencies
+}
+
+type PostChatMessageHandlerRequest struct {
+	Action  wspkg.Action `json:"action" example:"chat"`
+	Payload any          `json:"payload" swaggertype:"string" example:"Hello"`
+}
+
+type PostChatMessageHandlerResponse struct {
+	MessageID string `json:"message_id"`
+}
+
+func NewPostChatMessageHandler(deps PostChatMessageHandlerDependencies) (*PostChatMessageHandler, error) {
+	return &PostChatMessageHandler{deps: deps}, nil
+}
+
+// @ID PostChatMessage
+// @Summary Post chat message
+// @Description Send a chat action (defaults to "chat") without a websocket. Results are delivered to the session's event stream (GET /chats/{session_id}/events) and any open websocket. The session must be the conversation the event stream resolved to, otherwise 409 is returned.
+// @Tags chat
+// @Accept json
+// @Produce json
+// @Param session_id path string true "Session ID"
+// @Param request body PostChatMessageHandlerRequest true "Chat action"
+// @Success 202 {object} PostChatMessageHandlerResponse
+// @Failure 400 {object} http.Error
+// @Failure 401 {object} http.Error
+// @Failure 404 {object} http.Error
+// @Failure 409 {object} http.Error
+// @Failure 429 {object} http.Error
+// @Failure 500 {object} http.Error
+// @Failure 503 {object} http.Error
+// @Router /chats/{session_id}/messages [post]
+// @Security BearerAuth
+func (h *PostChatMessageHandler) Handle(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	userID, err := http.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(
+			http.NewError(ctx, err, "Unauthorized"),
+		)
+	}
+
+	sessionID := strings.Clone(c.Params("session_id"))
+	if sessionID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			http.NewError(ctx, errors.New("session_id is required"), "Bad Request"),
+		)
+	}
+
+	request := PostChatMessageHandlerRequest{}
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			http.NewError(ctx, err, "Invalid request body"),
+		)
+	}
+	if request.Action == "" {
+		request.Action = chathandler.ChatActionChat
+	}
+
+	if h.deps.Hub.Draining() {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(
+			http.NewError(ctx, errors.New("server is restarting"), "Service Unavailable"),
+		)
+	}
+
+	chat := &domain.Chat{}
+	if err := h.deps.DB.NewSelect().
+		Model(chat).
+		Where("session_id = ?", sessionID).
+		Where("user_id = ?", userID).
+		Scan(ctx); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(
+				http.NewError(ctx, err, "Chat not found for session_id: %v", sessionID),
+			)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to get chat for session_id: %v", sessionID),
+		)
+	}
+
+	// The event stream follows the current conversation, so posting anywhere else would never reach it.
+	current, err := chathandler.FindCurrentChat(ctx, h.deps.DB, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to get current chat"),
+		)
+	}
+	if current != nil && current.SessionID != sessionID {
+		return c.Status(fiber.StatusConflict).JSON(
+			http.NewError(ctx, errors.New("session is not the current conversation"), "Session %v is not the current conversation, use %v", sessionID, current.SessionID),
+		)
+	}
+
+	if allowed, retryAfter := h.deps.Hub.Limiter.AllowSession(userID, sessionID, request.Action); !allowed {
+		c.Set(fiber.HeaderRetryAfter, strconv.FormatInt(int64(retryAfter.Seconds())+1, 10))
+		return c.Status(fiber.StatusTooManyRequests).JSON(
+			http.NewError(ctx, errors.New("rate limited"), "Too many requests for action: %v", request.Action),
+		)
+	}
+
+	userMetadata, _ := http.GetUserMetadata(c)
+	message := wspkg.MessageWrapper{
+		Action:       request.Action,
+		Payload:      request.Payload,
+		SessionID:    sessionID,
+		MessageID:    uuid.New().String(),
+		ReceivedAt:   time.Now(),
+		Authorized:   true,
+		UserID:       userID,
+		UserMetadata: userMetadata,
+	}
+
+	if h.deps.Chat.IsImmediateAction(message.Action) {
+		h.dispatch(message)
+		return c.Status(fiber.StatusAccepted).JSON(PostChatMessageHandlerResponse{MessageID: message.MessageID})
+	}
+
+	worker := h.deps.Hub.AcquireWorker(sessionID)
+	h.deps.Hub.InFlightMessages.Add(1)
+	submitted := worker.Submit(func() {
+		defer h.deps.Hub.ReleaseWorker(worker)
+		defer h.deps.Hub.InFlightMessages.Add(-1)
+		h.dispatch(message)
+	})
+	if !submitted {
+		h.deps.Hub.InFlightMessages.Add(-1)
+		h.deps.Hub.ReleaseWorker(worker)
+		return c.Status(fiber.StatusServiceUnavailable).JSON(
+			http.NewError(ctx, errors.New("session worker queue is full"), "Session is busy"),
+		)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(PostChatMessageHandlerResponse{MessageID: message.MessageID})
+}
+
+func (h *PostChatMessageHandler) dispatch(request wspkg.MessageWrapper) {
+	response, _, err := h.deps.Chat.HandleMessage(nil, request)
+	if err != nil {
+		utils.Log(utils.ErrorLevel).CID(request.SessionID).RID(request.MessageID).Err(err).BT().Send("Failed to handle %v message", request.Action)
+		return
+	}
+	if slices.Contains(response.ControlFlags, wspkg.ControlFlagQuite) {
+		return
+	}
+	if err := h.deps.Hub.PublishSession(request.SessionID, response); err != nil {
+		utils.Log(utils.ErrorLevel).CID(request.SessionID).RID(request.MessageID).Err(err).BT().Send("Failed to publish %v response", request.Action)
+	}
+}
+
+func (h *PostChatMessageHandler) Identify() string {
+	return "post-chat-message"
+}
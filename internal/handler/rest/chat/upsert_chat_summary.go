@@ -11,6 +11,7 @@ import (
 	"github.com/solutionchallenge/ondaum-server/pkg/http"
 	"github.com/solutionchallenge/ondaum-server/pkg/llm"
 	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
 	"github.com/uptrace/bun"
 	"go.uber.org/fx"
 )
@@ -19,6 +20,7 @@ type UpsertChatSummaryHandlerDependencies struct {
 	fx.In
 	DB  *bun.DB
 	LLM llm.Client
+	Hub *wspkg.Hub
 }
 
 type UpsertChatSummaryHandlerResponse struct {
@@ -74,7 +76,7 @@ func (h *UpsertChatSummaryHandler) Handle(c *fiber.Ctx) error {
 	err = h.deps.DB.NewSelect().
 		Model(chat).
 		Relation("Histories", func(q *bun.SelectQuery) *bun.SelectQuery {
-			return q.Order("inserted_at ASC")
+			return domain.ActiveHistories(q).Order("sequence ASC")
 		}).
 		Where("session_id = ?", sessionID).
 		Where("user_id = ?", userID).
@@ -191,6 +193,12 @@ func (h *UpsertChatSummaryHandler) Handle(c *fiber.Ctx) error {
 		)
 	}
 	rowsAffected, _ := result.RowsAffected()
+	err = h.deps.Hub.Publish(userID, wspkg.BuildPushResponse(
+		sessionID, wspkg.PredefinedActionNotify, domain.NotifySummaryReady,
+	))
+	if err != nil {
+		utils.Log(utils.WarnLevel).Ctx(ctx).CID(sessionID).Err(err).BT().Send("Failed to publish summary ready")
+	}
 	response := &UpsertChatSummaryHandlerResponse{
 		Success:   true,
 		Created:   rowsAffected == 1,
@@ -0,0 +1,67 @@
+package sys
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/http"
+	"go.uber.org/fx"
+)
+
+type CancelJobHandlerDependencies struct {
+	fx.In
+	Future    future.Core
+	Scheduler *future.Scheduler
+}
+
+type CancelJobHandler struct {
+	deps CancelJobHandlerDependencies
+}
+
+func NewCancelJobHandler(deps CancelJobHandlerDependencies) (*CancelJobHandler, error) {
+	return &CancelJobHandler{deps: deps}, nil
+}
+
+func (h *CancelJobHandler) Handle(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	jobID := c.Params("job_id")
+
+	job, err := h.deps.Future.Inspect(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(
+				http.NewError(ctx, err, "Job not found for job_id: %v", jobID),
+			)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to inspect job for job_id: %v", jobID),
+		)
+	}
+	if job.Status != future.JobStatusWaiting && job.Status != future.JobStatusPending && job.Status != future.JobStatusRunning {
+		return c.Status(fiber.StatusConflict).JSON(
+			http.NewError(ctx, errors.New("job is not active"), "Job is already %v", job.Status),
+		)
+	}
+
+	if err := h.deps.Future.Cancel(ctx, jobID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to cancel job for job_id: %v", jobID),
+		)
+	}
+	// Jobs running on other replicas stop once their heartbeat notices the lease is gone.
+	h.deps.Scheduler.CancelRunning(jobID)
+
+	job, err = h.deps.Future.Inspect(ctx, jobID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to inspect job for job_id: %v", jobID),
+		)
+	}
+	return c.JSON(job)
+}
+
+func (h *CancelJobHandler) Identify() string {
+	return "cancel-job"
+}
@@ -0,0 +1,44 @@
+package sys
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/http"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	"go.uber.org/fx"
+)
+
+type DeleteScheduleHandlerDependencies struct {
+	fx.In
+	Future future.Core
+}
+
+type DeleteScheduleHandler struct {
+	deps      DeleteScheduleHandlerDependencies
+	schedules future.ScheduleCore
+}
+
+func NewDeleteScheduleHandler(deps DeleteScheduleHandlerDependencies) (*DeleteScheduleHandler, error) {
+	schedules, ok := deps.Future.(future.ScheduleCore)
+	if !ok {
+		return nil, utils.NewError("future core does not support schedules")
+	}
+	return &DeleteScheduleHandler{deps: deps, schedules: schedules}, nil
+}
+
+// Handle removes the schedule row; a schedule still defined in the config comes back on the next startup.
+func (h *DeleteScheduleHandler) Handle(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	name := c.Params("name")
+
+	if err := h.schedules.DeleteSchedule(ctx, name); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to delete schedule for name: %v", name),
+		)
+	}
+	return c.JSON(fiber.Map{"name": name, "deleted": true})
+}
+
+func (h *DeleteScheduleHandler) Identify() string {
+	return "delete-schedule"
+}
@@ -0,0 +1,51 @@
+package sys
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/http"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	"go.uber.org/fx"
+)
+
+type DisableScheduleHandlerDependencies struct {
+	fx.In
+	Future future.Core
+}
+
+type DisableScheduleHandler struct {
+	deps      DisableScheduleHandlerDependencies
+	schedules future.ScheduleCore
+}
+
+func NewDisableScheduleHandler(deps DisableScheduleHandlerDependencies) (*DisableScheduleHandler, error) {
+	schedules, ok := deps.Future.(future.ScheduleCore)
+	if !ok {
+		return nil, utils.NewError("future core does not support schedules")
+	}
+	return &DisableScheduleHandler{deps: deps, schedules: schedules}, nil
+}
+
+func (h *DisableScheduleHandler) Handle(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	name := c.Params("name")
+
+	if err := h.schedules.EnableSchedule(ctx, name, false); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(
+				http.NewError(ctx, err, "Schedule not found for name: %v", name),
+			)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to disable schedule for name: %v", name),
+		)
+	}
+	return c.JSON(fiber.Map{"name": name, "enabled": false})
+}
+
+func (h *DisableScheduleHandler) Identify() string {
+	return "disable-schedule"
+}
@@ -0,0 +1,51 @@
+package sys
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/http"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	"go.uber.org/fx"
+)
+
+type EnableScheduleHandlerDependencies struct {
+	fx.In
+	Future future.Core
+}
+
+type EnableScheduleHandler struct {
+	deps      EnableScheduleHandlerDependencies
+	schedules future.ScheduleCore
+}
+
+func NewEnableScheduleHandler(deps EnableScheduleHandlerDependencies) (*EnableScheduleHandler, error) {
+	schedules, ok := deps.Future.(future.ScheduleCore)
+	if !ok {
+		return nil, utils.NewError("future core does not support schedules")
+	}
+	return &EnableScheduleHandler{deps: deps, schedules: schedules}, nil
+}
+
+func (h *EnableScheduleHandler) Handle(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	name := c.Params("name")
+
+	if err := h.schedules.EnableSchedule(ctx, name, true); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(
+				http.NewError(ctx, err, "Schedule not found for name: %v", name),
+			)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to enable schedule for name: %v", name),
+		)
+	}
+	return c.JSON(fiber.Map{"name": name, "enabled": true})
+}
+
+func (h *EnableScheduleHandler) Identify() string {
+	return "enable-schedule"
+}
@@ -0,0 +1,34 @@
+package sys
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"go.uber.org/fx"
+)
+
+type GetAlertsHandlerDependencies struct {
+	fx.In
+	Admin *future.AdminAlertSink
+}
+
+type GetAlertsHandlerResponse struct {
+	Notifications []future.AdminNotification `json:"notifications"`
+}
+
+type GetAlertsHandler struct {
+	deps GetAlertsHandlerDependencies
+}
+
+func NewGetAlertsHandler(deps GetAlertsHandlerDependencies) (*GetAlertsHandler, error) {
+	return &GetAlertsHandler{deps: deps}, nil
+}
+
+func (h *GetAlertsHandler) Handle(c *fiber.Ctx) error {
+	return c.JSON(GetAlertsHandlerResponse{
+		Notifications: h.deps.Admin.Recent(),
+	})
+}
+
+func (h *GetAlertsHandler) Identify() string {
+	return "get-alerts"
+}
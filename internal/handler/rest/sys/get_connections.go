@@ -0,0 +1,41 @@
+package sys
+
+import (
+	"github.com/gofiber/fiber/v2"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
+	"go.uber.org/fx"
+)
+
+type GetConnectionsDependencies struct {
+	fx.In
+	Hub *wspkg.Hub
+}
+
+type GetConnectionsResponse struct {
+	OpenConnectionCount   int `json:"open_connection_count"`
+	ReapedConnectionCount int `json:"reaped_connection_count"`
+	SessionCount          int `json:"session_count"`
+	UserCount             int `json:"user_count"`
+}
+
+type GetConnectionsHandler struct {
+	deps GetConnectionsDependencies
+}
+
+func NewGetConnectionsHandler(deps GetConnectionsDependencies) (*GetConnectionsHandler, error) {
+	return &GetConnectionsHandler{deps: deps}, nil
+}
+
+func (h *GetConnectionsHandler) Handle(c *fiber.Ctx) error {
+	statistics := h.deps.Hub.Statistics()
+	return c.JSON(GetConnectionsResponse{
+		OpenConnectionCount:   int(statistics.OpenConnections),
+		ReapedConnectionCount: int(statistics.ReapedConnections),
+		SessionCount:          statistics.Sessions,
+		UserCount:             statistics.Users,
+	})
+}
+
+func (h *GetConnectionsHandler) Identify() string {
+	return "get-connections"
+}
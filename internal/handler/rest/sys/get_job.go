@@ -0,0 +1,62 @@
+package sys
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/http"
+	"go.uber.org/fx"
+)
+
+type GetJobHandlerDependencies struct {
+	fx.In
+	Future future.Core
+}
+
+type GetJobHandlerResponse struct {
+	Job     future.Job          `json:"job"`
+	History []future.JobAttempt `json:"history"`
+}
+
+type GetJobHandler struct {
+	deps GetJobHandlerDependencies
+}
+
+func NewGetJobHandler(deps GetJobHandlerDependencies) (*GetJobHandler, error) {
+	return &GetJobHandler{deps: deps}, nil
+}
+
+func (h *GetJobHandler) Handle(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	jobID := c.Params("job_id")
+
+	job, err := h.deps.Future.Inspect(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(
+				http.NewError(ctx, err, "Job not found for job_id: %v", jobID),
+			)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to inspect job for job_id: %v", jobID),
+		)
+	}
+
+	history, err := h.deps.Future.History(ctx, jobID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to get job history for job_id: %v", jobID),
+		)
+	}
+
+	return c.JSON(GetJobHandlerResponse{
+		Job:     *job,
+		History: history,
+	})
+}
+
+func (h *GetJobHandler) Identify() string {
+	return "get-job"
+}
@@ -3,6 +3,7 @@ package sys
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/solutionchallenge/ondaum-server/pkg/llm"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
 	"go.uber.org/fx"
 )
 
@@ -12,10 +13,13 @@ type GetTokensDependencies struct {
 }
 
 type GetTokensResponse struct {
-	TotalTokenCount      int `json:"total_token_count"`
-	PromptTokenCount     int `json:"prompt_token_count"`
-	CompletionTokenCount int `json:"completion_token_count"`
-	CachedTokenCount     int `json:"cached_token_count"`
+	TotalTokenCount      int     `json:"total_token_count"`
+	PromptTokenCount     int     `json:"prompt_token_count"`
+	CompletionTokenCount int     `json:"completion_token_count"`
+	CachedTokenCount     int     `json:"cached_token_count"`
+	CacheHitCount        int     `json:"cache_hit_count"`
+	CacheMissCount       int     `json:"cache_miss_count"`
+	CacheHitRate         float64 `json:"cache_hit_rate"`
 }
 
 type GetTokensHandler struct {
@@ -33,6 +37,9 @@ func (h *GetTokensHandler) Handle(c *fiber.Ctx) error {
 	response.PromptTokenCount = int(statistics.PromptTokens)
 	response.CompletionTokenCount = int(statistics.CompletionTokens)
 	response.CachedTokenCount = int(statistics.CachedTokens)
+	response.CacheHitCount = int(statistics.CacheHits)
+	response.CacheMissCount = int(statistics.CacheMisses)
+	response.CacheHitRate = utils.RoundTo(statistics.CacheHitRate(), 4)
 	return c.JSON(response)
 }
 
@@ -0,0 +1,82 @@
+package sys
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/http"
+	"go.uber.org/fx"
+)
+
+type ListDeadLettersHandlerDependencies struct {
+	fx.In
+	Future future.Core
+}
+
+type ListDeadLettersHandlerResponse struct {
+	DeadLetters []future.DeadLetter `json:"dead_letters"`
+	Total       int                 `json:"total"`
+	Page        int                 `json:"page"`
+	PageSize    int                 `json:"page_size"`
+}
+
+type ListDeadLettersHandler struct {
+	deps ListDeadLettersHandlerDependencies
+}
+
+func NewListDeadLettersHandler(deps ListDeadLettersHandlerDependencies) (*ListDeadLettersHandler, error) {
+	return &ListDeadLettersHandler{deps: deps}, nil
+}
+
+func (h *ListDeadLettersHandler) Handle(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			http.NewError(ctx, errors.New("invalid page"), "Invalid page value. Use a positive integer"),
+		)
+	}
+	pageSize, err := strconv.Atoi(c.Query("page_size", strconv.Itoa(DefaultJobPageSize)))
+	if err != nil || pageSize < 1 || pageSize > MaxJobPageSize {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			http.NewError(ctx, errors.New("invalid page_size"), "Invalid page_size value. Use 1 to %d", MaxJobPageSize),
+		)
+	}
+
+	jobs, total, err := h.deps.Future.List(ctx, future.JobFilter{
+		Status:     future.JobStatusDeadLettered,
+		ActionType: future.JobType(c.Query("action_type")),
+		Offset:     (page - 1) * pageSize,
+		Limit:      pageSize,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to list dead-lettered jobs"),
+		)
+	}
+
+	letters := make([]future.DeadLetter, 0, len(jobs))
+	for _, job := range jobs {
+		history, err := h.deps.Future.History(ctx, job.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				http.NewError(ctx, err, "Failed to get job history for job_id: %v", job.ID),
+			)
+		}
+		letters = append(letters, future.NewDeadLetter(job, history))
+	}
+
+	return c.JSON(ListDeadLettersHandlerResponse{
+		DeadLetters: letters,
+		Total:       total,
+		Page:        page,
+		PageSize:    pageSize,
+	})
+}
+
+func (h *ListDeadLettersHandler) Identify() string {
+	return "list-dead-letters"
+}
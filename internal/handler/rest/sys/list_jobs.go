@@ -0,0 +1,95 @@
+package sys
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/http"
+	"go.uber.org/fx"
+)
+
+const (
+	DefaultJobPageSize = 50
+	MaxJobPageSize     = 200
+)
+
+type ListJobsHandlerDependencies struct {
+	fx.In
+	Future future.Core
+}
+
+type ListJobsHandlerResponse struct {
+	Jobs     []future.Job `json:"jobs"`
+	Total    int          `json:"total"`
+	Page     int          `json:"page"`
+	PageSize int          `json:"page_size"`
+}
+
+type ListJobsHandler struct {
+	deps ListJobsHandlerDependencies
+}
+
+func NewListJobsHandler(deps ListJobsHandlerDependencies) (*ListJobsHandler, error) {
+	return &ListJobsHandler{deps: deps}, nil
+}
+
+func (h *ListJobsHandler) Handle(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	page, err := strconv.Atoi(c.Query("page", "1"))
+	if err != nil || page < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			http.NewError(ctx, errors.New("invalid page"), "Invalid page value. Use a positive integer"),
+		)
+	}
+	pageSize, err := strconv.Atoi(c.Query("page_size", strconv.Itoa(DefaultJobPageSize)))
+	if err != nil || pageSize < 1 || pageSize > MaxJobPageSize {
+		return c.Status(fiber.StatusBadRequest).JSON(
+			http.NewError(ctx, errors.New("invalid page_size"), "Invalid page_size value. Use 1 to %d", MaxJobPageSize),
+		)
+	}
+
+	filter := future.JobFilter{
+		Status:     future.JobStatus(c.Query("status")),
+		ActionType: future.JobType(c.Query("action_type")),
+		Offset:     (page - 1) * pageSize,
+		Limit:      pageSize,
+	}
+	if datetimeGte := c.Query("datetime_gte"); datetimeGte != "" {
+		filter.From, err = time.Parse(time.RFC3339, datetimeGte)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				http.NewError(ctx, err, "Invalid datetime_gte format. Use YYYY-MM-DDTHH:mm:ssZ"),
+			)
+		}
+	}
+	if datetimeLte := c.Query("datetime_lte"); datetimeLte != "" {
+		filter.To, err = time.Parse(time.RFC3339, datetimeLte)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				http.NewError(ctx, err, "Invalid datetime_lte format. Use YYYY-MM-DDTHH:mm:ssZ"),
+			)
+		}
+	}
+
+	jobs, total, err := h.deps.Future.List(ctx, filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to list jobs"),
+		)
+	}
+
+	return c.JSON(ListJobsHandlerResponse{
+		Jobs:     jobs,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	})
+}
+
+func (h *ListJobsHandler) Identify() string {
+	return "list-jobs"
+}
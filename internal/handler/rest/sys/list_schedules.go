@@ -0,0 +1,47 @@
+package sys
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/http"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	"go.uber.org/fx"
+)
+
+type ListSchedulesHandlerDependencies struct {
+	fx.In
+	Future future.Core
+}
+
+type ListSchedulesHandlerResponse struct {
+	Schedules []future.Schedule `json:"schedules"`
+}
+
+type ListSchedulesHandler struct {
+	deps      ListSchedulesHandlerDependencies
+	schedules future.ScheduleCore
+}
+
+func NewListSchedulesHandler(deps ListSchedulesHandlerDependencies) (*ListSchedulesHandler, error) {
+	schedules, ok := deps.Future.(future.ScheduleCore)
+	if !ok {
+		return nil, utils.NewError("future core does not support schedules")
+	}
+	return &ListSchedulesHandler{deps: deps, schedules: schedules}, nil
+}
+
+func (h *ListSchedulesHandler) Handle(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	schedules, err := h.schedules.ListSchedules(ctx)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to list schedules"),
+		)
+	}
+	return c.JSON(ListSchedulesHandlerResponse{Schedules: schedules})
+}
+
+func (h *ListSchedulesHandler) Identify() string {
+	return "list-schedules"
+}
@@ -0,0 +1,77 @@
+package sys
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/http"
+	"go.uber.org/fx"
+)
+
+const (
+	PurgeJobBatchSize = 100
+)
+
+type PurgeJobsHandlerDependencies struct {
+	fx.In
+	Future future.Core
+}
+
+type PurgeJobsHandlerResponse struct {
+	PurgedCount int `json:"purged_count"`
+}
+
+type PurgeJobsHandler struct {
+	deps PurgeJobsHandlerDependencies
+}
+
+func NewPurgeJobsHandler(deps PurgeJobsHandlerDependencies) (*PurgeJobsHandler, error) {
+	return &PurgeJobsHandler{deps: deps}, nil
+}
+
+func (h *PurgeJobsHandler) Handle(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	filter := future.JobFilter{
+		Status: future.JobStatusCompleted,
+		Limit:  PurgeJobBatchSize,
+	}
+	if datetimeLte := c.Query("datetime_lte"); datetimeLte != "" {
+		parsed, err := time.Parse(time.RFC3339, datetimeLte)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(
+				http.NewError(ctx, err, "Invalid datetime_lte format. Use YYYY-MM-DDTHH:mm:ssZ"),
+			)
+		}
+		filter.To = parsed
+	}
+
+	purged, previous := 0, -1
+	for {
+		jobs, total, err := h.deps.Future.List(ctx, filter)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				http.NewError(ctx, err, "Failed to list completed jobs"),
+			)
+		}
+		if len(jobs) == 0 || total == previous {
+			break
+		}
+		previous = total
+		for _, job := range jobs {
+			if err := h.deps.Future.DeletePermanently(ctx, job.ID); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(
+					http.NewError(ctx, err, "Failed to purge job for job_id: %v (%d purged)", job.ID, purged),
+				)
+			}
+			purged++
+		}
+	}
+
+	return c.JSON(PurgeJobsHandlerResponse{PurgedCount: purged})
+}
+
+func (h *PurgeJobsHandler) Identify() string {
+	return "purge-jobs"
+}
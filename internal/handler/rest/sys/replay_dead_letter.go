@@ -0,0 +1,58 @@
+package sys
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/http"
+	"go.uber.org/fx"
+)
+
+type ReplayDeadLetterHandlerDependencies struct {
+	fx.In
+	Future future.Core
+}
+
+type ReplayDeadLetterHandler struct {
+	deps ReplayDeadLetterHandlerDependencies
+}
+
+func NewReplayDeadLetterHandler(deps ReplayDeadLetterHandlerDependencies) (*ReplayDeadLetterHandler, error) {
+	return &ReplayDeadLetterHandler{deps: deps}, nil
+}
+
+func (h *ReplayDeadLetterHandler) Handle(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	jobID := c.Params("job_id")
+
+	replayed, err := h.deps.Future.Replay(ctx, jobID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to replay job for job_id: %v", jobID),
+		)
+	}
+
+	job, err := h.deps.Future.Inspect(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(
+				http.NewError(ctx, err, "Job not found for job_id: %v", jobID),
+			)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to inspect job for job_id: %v", jobID),
+		)
+	}
+	if !replayed {
+		return c.Status(fiber.StatusConflict).JSON(
+			http.NewError(ctx, errors.New("job is not dead-lettered"), "Job is %v", job.Status),
+		)
+	}
+	return c.JSON(job)
+}
+
+func (h *ReplayDeadLetterHandler) Identify() string {
+	return "replay-dead-letter"
+}
@@ -0,0 +1,70 @@
+package sys
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/http"
+	"go.uber.org/fx"
+)
+
+const (
+	ReplayDeadLetterBatchSize = 100
+)
+
+type ReplayDeadLettersHandlerDependencies struct {
+	fx.In
+	Future future.Core
+}
+
+type ReplayDeadLettersHandlerResponse struct {
+	ReplayedIDs []string `json:"replayed_ids"`
+}
+
+type ReplayDeadLettersHandler struct {
+	deps ReplayDeadLettersHandlerDependencies
+}
+
+func NewReplayDeadLettersHandler(deps ReplayDeadLettersHandlerDependencies) (*ReplayDeadLettersHandler, error) {
+	return &ReplayDeadLettersHandler{deps: deps}, nil
+}
+
+func (h *ReplayDeadLettersHandler) Handle(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+
+	filter := future.JobFilter{
+		Status:     future.JobStatusDeadLettered,
+		ActionType: future.JobType(c.Query("action_type")),
+		Limit:      ReplayDeadLetterBatchSize,
+	}
+
+	replayed, previous := []string{}, -1
+	for {
+		jobs, total, err := h.deps.Future.List(ctx, filter)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(
+				http.NewError(ctx, err, "Failed to list dead-lettered jobs"),
+			)
+		}
+		if len(jobs) == 0 || total == previous {
+			break
+		}
+		previous = total
+		for _, job := range jobs {
+			ok, err := h.deps.Future.Replay(ctx, job.ID)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(
+					http.NewError(ctx, err, "Failed to replay job for job_id: %v (%d replayed)", job.ID, len(replayed)),
+				)
+			}
+			if ok {
+				replayed = append(replayed, job.ID)
+			}
+		}
+	}
+
+	return c.JSON(ReplayDeadLettersHandlerResponse{ReplayedIDs: replayed})
+}
+
+func (h *ReplayDeadLettersHandler) Identify() string {
+	return "replay-dead-letters"
+}
@@ -0,0 +1,70 @@
+package sys
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/http"
+	"go.uber.org/fx"
+)
+
+type RetryJobHandlerDependencies struct {
+	fx.In
+	Future future.Core
+}
+
+type RetryJobHandler struct {
+	deps RetryJobHandlerDependencies
+}
+
+func NewRetryJobHandler(deps RetryJobHandlerDependencies) (*RetryJobHandler, error) {
+	return &RetryJobHandler{deps: deps}, nil
+}
+
+func (h *RetryJobHandler) Handle(c *fiber.Ctx) error {
+	ctx := c.UserContext()
+	jobID := c.Params("job_id")
+
+	job, err := h.deps.Future.Inspect(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(
+				http.NewError(ctx, err, "Job not found for job_id: %v", jobID),
+			)
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to inspect job for job_id: %v", jobID),
+		)
+	}
+	if !future.CanRequeue(job.Status) {
+		return c.Status(fiber.StatusConflict).JSON(
+			http.NewError(ctx, errors.New("job is not finished unsuccessfully"), "Job is %v", job.Status),
+		)
+	}
+
+	requeued, err := h.deps.Future.Requeue(ctx, jobID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to retry job for job_id: %v", jobID),
+		)
+	}
+	if !requeued {
+		return c.Status(fiber.StatusConflict).JSON(
+			http.NewError(ctx, errors.New("job cannot be retried"), "Job has outstanding dependencies or changed status"),
+		)
+	}
+
+	job, err = h.deps.Future.Inspect(ctx, jobID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(
+			http.NewError(ctx, err, "Failed to inspect job for job_id: %v", jobID),
+		)
+	}
+	return c.JSON(job)
+}
+
+func (h *RetryJobHandler) Identify() string {
+	return "retry-job"
+}
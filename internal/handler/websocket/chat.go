@@ -1,22 +1,15 @@
 package websocket
 
 import (
-	"github.com/benbjohnson/clock"
 	fiberws "github.com/gofiber/websocket/v2"
-	impl "github.com/solutionchallenge/ondaum-server/internal/handler/websocket/chat"
-	ftpkg "github.com/solutionchallenge/ondaum-server/pkg/future"
-	"github.com/solutionchallenge/ondaum-server/pkg/llm"
+	impl "github.com/solutionchallenge/ondaum-server/internal/handler/chat"
 	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
-	"github.com/uptrace/bun"
 	"go.uber.org/fx"
 )
 
 type ChatHandlerDependencies struct {
 	fx.In
-	Future *ftpkg.Scheduler
-	LLM    llm.Client
-	DB     *bun.DB
-	Clock  clock.Clock
+	Chat *impl.Service
 }
 
 type ChatHandler struct {
@@ -35,24 +28,29 @@ func NewChatHandler(deps ChatHandlerDependencies) (*ChatHandler, error) {
 // @Produce      json
 // @Param        session_id query string false "Websocket Session ID (optional; if not provided, the server will use the most recent non-archived conversation or create a new one if none exists)"
 // @Param        access_token query string false "Access Token (optional; if not provided, the server will try to get the access token from the request header)"
+// @Param        Sec-WebSocket-Protocol header string false "Websocket subprotocol (ondaum.json.v1 or ondaum.msgpack.v1; defaults to ondaum.json.v1)"
 // @Success      200 {object} wspkg.ResponseWrapper
 // @Failure      426 {object} http.Error
 // @Router       /_ws/chat [get]
 // @Security     BearerAuth
 func (h *ChatHandler) HandleMessage(c *fiberws.Conn, request wspkg.MessageWrapper) (wspkg.ResponseWrapper, bool, error) {
-	return impl.HandleMessage(h.deps.DB, h.deps.Clock, h.deps.LLM, h.deps.Future, request)
+	return h.deps.Chat.HandleMessage(c, request)
 }
 
-func (h *ChatHandler) HandleConnect(c *fiberws.Conn, request wspkg.ConnectWrapper) (wspkg.ResponseWrapper, string, error) {
-	return impl.HandleConnect(h.deps.DB, h.deps.Clock, request)
+func (h *ChatHandler) IsImmediateAction(action wspkg.Action) bool {
+	return h.deps.Chat.IsImmediateAction(action)
+}
+
+func (h *ChatHandler) HandleConnect(_ *fiberws.Conn, request wspkg.ConnectWrapper) (wspkg.ResponseWrapper, string, error) {
+	return h.deps.Chat.HandleConnect(request)
 }
 
 func (h *ChatHandler) HandleClose(_ *fiberws.Conn, request wspkg.CloseWrapper) {
-	impl.HandleClose(h.deps.DB, request, h.deps.LLM)
+	h.deps.Chat.HandleClose(request)
 }
 
-func (h *ChatHandler) HandlePing(c *fiberws.Conn, request wspkg.PingWrapper) (wspkg.ResponseWrapper, bool, error) {
-	return impl.HandlePing(h.deps.DB, request)
+func (h *ChatHandler) HandlePing(_ *fiberws.Conn, request wspkg.PingWrapper) (wspkg.ResponseWrapper, bool, error) {
+	return h.deps.Chat.HandlePing(request)
 }
 
 func (h *ChatHandler) Identify() string {
@@ -0,0 +1,288 @@
+package websocket
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/benbjohnson/clock"
+	fiberws "github.com/gofiber/websocket/v2"
+	impl "github.com/solutionchallenge/ondaum-server/internal/handler/chat"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
+	"github.com/solutionchallenge/ondaum-server/pkg/websocket/wstest"
+	"github.com/solutionchallenge/ondaum-server/test/mock"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/mysqldialect"
+	"go.uber.org/mock/gomock"
+)
+
+var testcases_ChatHandler = []struct {
+	name     string
+	setup    func(t *testing.T, tester *tester_ChatHandler)
+	dial     []wstest.DialOption
+	scenario func(t *testing.T, client *wstest.Client)
+}{
+	{
+		name:  "Failure Case - Unauthorized Connect",
+		setup: func(t *testing.T, tester *tester_ChatHandler) {},
+		dial:  []wstest.DialOption{},
+		scenario: func(t *testing.T, client *wstest.Client) {
+			client.ExpectClose(fiberws.CloseNormalClosure)
+		},
+	},
+	{
+		name: "Failure Case - User Not Found",
+		setup: func(t *testing.T, tester *tester_ChatHandler) {
+			tester.databaseController.
+				ExpectQuery("SELECT .* FROM `users`").
+				WillReturnError(sql.ErrNoRows)
+		},
+		dial: []wstest.DialOption{wstest.WithUserID(1)},
+		scenario: func(t *testing.T, client *wstest.Client) {
+			client.ExpectClose(fiberws.CloseNormalClosure)
+		},
+	},
+	{
+		name: "Success Case - New Conversation",
+		setup: func(t *testing.T, tester *tester_ChatHandler) {
+			tester.expectAuthorized()
+			tester.databaseController.
+				ExpectQuery("SELECT .* FROM `chats`").
+				WillReturnError(sql.ErrNoRows)
+			tester.databaseController.
+				ExpectExec("INSERT INTO `chats`").
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			tester.mockedLLM.EXPECT().Close("session-new").Return(nil)
+		},
+		dial: []wstest.DialOption{wstest.WithUserID(1), wstest.WithSessionID("session-new")},
+		scenario: func(t *testing.T, client *wstest.Client) {
+			frame := client.AwaitPayload(wspkg.PredefinedActionNotify, impl.ChatPayloadNotifyNewConversation)
+			if frame.Protocol != wspkg.ProtocolJSON {
+				t.Fatalf("expected protocol %s, got %s", wspkg.ProtocolJSON, frame.Protocol)
+			}
+			client.Close()
+		},
+	},
+	{
+		name: "Success Case - Existing Conversation With MessagePack",
+		setup: func(t *testing.T, tester *tester_ChatHandler) {
+			tester.expectAuthorized()
+			tester.expectChat("session-existing", false)
+			tester.mockedLLM.EXPECT().Close("session-existing").Return(nil)
+		},
+		dial: []wstest.DialOption{wstest.WithUserID(1), wstest.WithProtocol(wspkg.ProtocolMsgpack)},
+		scenario: func(t *testing.T, client *wstest.Client) {
+			frame := client.AwaitPayload(wspkg.PredefinedActionNotify, impl.ChatPayloadNotifyExistingConversation)
+			if frame.Protocol != wspkg.ProtocolMsgpack {
+				t.Fatalf("expected protocol %s, got %s", wspkg.ProtocolMsgpack, frame.Protocol)
+			}
+			client.Close()
+		},
+	},
+	{
+		name: "Success Case - Ping",
+		setup: func(t *testing.T, tester *tester_ChatHandler) {
+			tester.expectAuthorized()
+			tester.expectChat("session-ping", false)
+			tester.expectAuthorized()
+			tester.mockedLLM.EXPECT().Close("session-ping").Return(nil)
+		},
+		dial: []wstest.DialOption{wstest.WithUserID(1)},
+		scenario: func(t *testing.T, client *wstest.Client) {
+			client.AwaitPayload(wspkg.PredefinedActionNotify, impl.ChatPayloadNotifyExistingConversation)
+			client.Ping()
+			client.Send(impl.ChatActionPing, nil)
+			client.ExpectSilence(200 * time.Millisecond)
+			client.Close()
+		},
+	},
+	{
+		name: "Success Case - Ack Without Response",
+		setup: func(t *testing.T, tester *tester_ChatHandler) {
+			tester.expectAuthorized()
+			tester.expectChat("session-ack", false)
+			tester.expectAuthorized()
+			tester.databaseController.
+				ExpectExec("UPDATE `chats` .*acked_sequence").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			tester.mockedLLM.EXPECT().Close("session-ack").Return(nil)
+		},
+		dial: []wstest.DialOption{wstest.WithUserID(1)},
+		scenario: func(t *testing.T, client *wstest.Client) {
+			client.AwaitPayload(wspkg.PredefinedActionNotify, impl.ChatPayloadNotifyExistingConversation)
+			client.Send(impl.ChatActionAck, 5)
+			client.ExpectSilence(200 * time.Millisecond)
+			client.Close()
+		},
+	},
+	{
+		name: "Failure Case - Unknown Action",
+		setup: func(t *testing.T, tester *tester_ChatHandler) {
+			tester.expectAuthorized()
+			tester.expectChat("session-unknown", false)
+			tester.expectAuthorized()
+			tester.mockedLLM.EXPECT().Close("session-unknown").Return(nil)
+		},
+		dial: []wstest.DialOption{wstest.WithUserID(1)},
+		scenario: func(t *testing.T, client *wstest.Client) {
+			client.AwaitPayload(wspkg.PredefinedActionNotify, impl.ChatPayloadNotifyExistingConversation)
+			client.Send(wspkg.Action("unknown"), nil)
+			frame := client.Await(wspkg.PredefinedActionReject)
+			payload, ok := frame.Payload.(map[string]any)
+			if !ok || payload["reason"] != string(wspkg.RejectReasonUnknownAction) {
+				t.Fatalf("expected %s rejection, got %v", wspkg.RejectReasonUnknownAction, frame.Payload)
+			}
+			client.Close()
+		},
+	},
+	{
+		name: "Failure Case - Resume Archived Conversation",
+		setup: func(t *testing.T, tester *tester_ChatHandler) {
+			tester.expectAuthorized()
+			tester.expectChat("session-archived", false)
+			tester.expectAuthorized()
+			tester.expectChat("session-archived", true)
+			tester.mockedLLM.EXPECT().Close("session-archived").Return(nil)
+		},
+		dial: []wstest.DialOption{wstest.WithUserID(1)},
+		scenario: func(t *testing.T, client *wstest.Client) {
+			client.AwaitPayload(wspkg.PredefinedActionNotify, impl.ChatPayloadNotifyExistingConversation)
+			client.Send(impl.ChatActionResume, nil)
+			client.AwaitPayload(wspkg.PredefinedActionNotify, impl.ChatPayloadNotifyConversationArchived)
+			client.ExpectClose(fiberws.CloseNormalClosure)
+		},
+	},
+	{
+		name: "Failure Case - Chat On Archived Conversation",
+		setup: func(t *testing.T, tester *tester_ChatHandler) {
+			tester.expectAuthorized()
+			tester.expectChat("session-archived", false)
+			tester.expectAuthorized()
+			tester.databaseController.ExpectBegin()
+			tester.expectAuthorized()
+			tester.expectChat("session-archived", true)
+			tester.databaseController.ExpectCommit()
+			tester.mockedLLM.EXPECT().Close("session-archived").Return(nil)
+		},
+		dial: []wstest.DialOption{wstest.WithUserID(1)},
+		scenario: func(t *testing.T, client *wstest.Client) {
+			client.AwaitPayload(wspkg.PredefinedActionNotify, impl.ChatPayloadNotifyExistingConversation)
+			client.Send(impl.ChatActionChat, "hello")
+			client.Await(impl.ChatActionStatus)
+			client.ExpectClose(fiberws.CloseNormalClosure)
+		},
+	},
+}
+
+func Test_ChatHandler(t *testing.T) {
+	for _, testcase := range testcases_ChatHandler {
+		t.Run(testcase.name, func(t *testing.T) {
+			tester, err := prepareChatHandlerForTest(t)
+			if err != nil {
+				t.Fatalf("failed to prepare tester: %v", err)
+			}
+			testcase.setup(t, tester)
+
+			server := wstest.NewServer(t, tester.handler, wstest.WithHub(tester.hub))
+			client := server.Dial(t, testcase.dial...)
+			testcase.scenario(t, client)
+
+			waitForConnections(t, tester.hub)
+			if err := tester.databaseController.ExpectationsWereMet(); err != nil {
+				t.Fatalf("unmet database expectations: %v", err)
+			}
+		})
+	}
+}
+
+type tester_ChatHandler struct {
+	mockController     *gomock.Controller
+	databaseController sqlmock.Sqlmock
+	mockedDatabase     *sql.DB
+	mockedORM          *bun.DB
+	mockedLLM          *mock.MockLLMClient
+	mockedClock        *clock.Mock
+	hub                *wspkg.Hub
+	handler            *ChatHandler
+}
+
+func (tester *tester_ChatHandler) expectAuthorized() {
+	tester.databaseController.
+		ExpectQuery("SELECT .* FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
+func (tester *tester_ChatHandler) expectChat(sessionID string, archived bool) {
+	archivedAt := sql.NullTime{}
+	if archived {
+		archivedAt = sql.NullTime{Time: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+	tester.databaseController.
+		ExpectQuery("SELECT .* FROM `chats`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "session_id", "archived_at"}).
+			AddRow(1, 1, sessionID, archivedAt),
+		)
+}
+
+func prepareChatHandlerForTest(t *testing.T) (*tester_ChatHandler, error) {
+	mockController := gomock.NewController(t)
+
+	mockedDatabase, databaseController, err := sqlmock.New()
+	if err != nil {
+		return nil, err
+	}
+	databaseController.
+		ExpectQuery("SELECT version()").
+		WithoutArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"version()"}).AddRow("8.0.28"))
+	mockedORM := bun.NewDB(mockedDatabase, mysqldialect.New())
+	t.Cleanup(func() {
+		mockedORM.Close()
+	})
+
+	mockedLLM := mock.NewMockLLMClient(mockController)
+
+	mockedClock := clock.NewMock()
+
+	hub := wspkg.NewHub(wspkg.Config{}, nil)
+
+	service, err := impl.NewService(impl.ServiceDependencies{
+		DB:    mockedORM,
+		LLM:   mockedLLM,
+		Clock: mockedClock,
+		Hub:   hub,
+
+		Generations: impl.NewGenerationRegistry(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	handler, err := NewChatHandler(ChatHandlerDependencies{Chat: service})
+	if err != nil {
+		return nil, err
+	}
+
+	return &tester_ChatHandler{
+		mockController:     mockController,
+		databaseController: databaseController,
+		mockedDatabase:     mockedDatabase,
+		mockedORM:          mockedORM,
+		mockedLLM:          mockedLLM,
+		mockedClock:        mockedClock,
+		hub:                hub,
+		handler:            handler,
+	}, nil
+}
+
+func waitForConnections(t *testing.T, hub *wspkg.Hub) {
+	t.Helper()
+	deadline := time.Now().Add(wstest.DefaultTimeout)
+	for hub.OpenConnections.Load() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for connections to close")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -22,4 +22,17 @@ var Collection = []database.Migration{
 	sql.MigrationUser014AlterDiagnosisTable,
 	sql.MigrationUser015AlterChatTable,
 	sql.MigrationUser016UpdateChatHistoryRow,
+	sql.MigrationUser017CreateWebsocketMessageTable,
+	sql.MigrationUser018AlterChatHistoryTable,
+	sql.MigrationUser019UpdateChatHistoryRow,
+	sql.MigrationUser020AlterChatTable,
+	sql.MigrationUser021AlterChatHistoryTable,
+	sql.MigrationUser022AlterFutureJobTable,
+	sql.MigrationUser023AlterFutureJobTable,
+	sql.MigrationUser024CreateFutureScheduleTable,
+	sql.MigrationUser025CreateFutureJobAttemptTable,
+	sql.MigrationUser026UpdateFutureJobRow,
+	sql.MigrationUser027AlterFutureJobAttemptTable,
+	sql.MigrationUser028AlterFutureJobTable,
+	sql.MigrationUser029AlterChatHistoryTable,
 }
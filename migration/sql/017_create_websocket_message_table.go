@@ -0,0 +1,11 @@
+package sql
+
+import (
+	"github.com/solutionchallenge/ondaum-server/pkg/database"
+	dbbackplane "github.com/solutionchallenge/ondaum-server/pkg/websocket/backplane/database"
+)
+
+var MigrationUser017CreateWebsocketMessageTable = database.Migration{
+	Name:  "user.017.create_websocket_message_table",
+	Query: dbbackplane.WebsocketMessageTableCreationSQL,
+}
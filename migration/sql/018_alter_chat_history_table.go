@@ -0,0 +1,13 @@
+package sql
+
+import "github.com/solutionchallenge/ondaum-server/pkg/database"
+
+const sqlUser018AlterChatHistoryTable = `
+ALTER TABLE chat_histories
+ADD COLUMN sequence BIGINT NOT NULL DEFAULT 0 AFTER message_id,
+ADD INDEX idx_chat_sequence (chat_id, sequence);`
+
+var MigrationUser018AlterChatHistoryTable = database.Migration{
+	Name:  "user.018.alter_chat_history_table",
+	Query: sqlUser018AlterChatHistoryTable,
+}
@@ -0,0 +1,11 @@
+package sql
+
+import "github.com/solutionchallenge/ondaum-server/pkg/database"
+
+const sqlUser019UpdateChatHistoryRow = `
+UPDATE chat_histories SET sequence = id WHERE sequence = 0;`
+
+var MigrationUser019UpdateChatHistoryRow = database.Migration{
+	Name:  "user.019.update_chat_history_row",
+	Query: sqlUser019UpdateChatHistoryRow,
+}
@@ -0,0 +1,12 @@
+package sql
+
+import "github.com/solutionchallenge/ondaum-server/pkg/database"
+
+const sqlUser020AlterChatTable = `
+ALTER TABLE chats
+ADD COLUMN acked_sequence BIGINT NOT NULL DEFAULT 0 AFTER chat_duration`
+
+var MigrationUser020AlterChatTable = database.Migration{
+	Name:  "user.020.alter_chat_table",
+	Query: sqlUser020AlterChatTable,
+}
@@ -0,0 +1,12 @@
+package sql
+
+import "github.com/solutionchallenge/ondaum-server/pkg/database"
+
+const sqlUser021AlterChatHistoryTable = `
+ALTER TABLE chat_histories
+ADD COLUMN cancelled_at DATETIME NULL AFTER message_id;`
+
+var MigrationUser021AlterChatHistoryTable = database.Migration{
+	Name:  "user.021.alter_chat_history_table",
+	Query: sqlUser021AlterChatHistoryTable,
+}
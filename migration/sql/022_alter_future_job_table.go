@@ -0,0 +1,13 @@
+package sql
+
+import "github.com/solutionchallenge/ondaum-server/pkg/database"
+
+const sqlUser022AlterFutureJobTable = `
+ALTER TABLE future_jobs
+ADD COLUMN next_attempt_at DATETIME NULL AFTER triggered_at,
+ADD COLUMN attempts INT NOT NULL DEFAULT 0 AFTER next_attempt_at`
+
+var MigrationUser022AlterFutureJobTable = database.Migration{
+	Name:  "user.022.alter_future_job_table",
+	Query: sqlUser022AlterFutureJobTable,
+}
@@ -0,0 +1,13 @@
+package sql
+
+import "github.com/solutionchallenge/ondaum-server/pkg/database"
+
+const sqlUser023AlterFutureJobTable = `
+ALTER TABLE future_jobs
+ADD COLUMN worker_id VARCHAR(255) NULL AFTER attempts,
+ADD COLUMN lease_expires_at DATETIME NULL AFTER worker_id`
+
+var MigrationUser023AlterFutureJobTable = database.Migration{
+	Name:  "user.023.alter_future_job_table",
+	Query: sqlUser023AlterFutureJobTable,
+}
@@ -0,0 +1,11 @@
+package sql
+
+import (
+	"github.com/solutionchallenge/ondaum-server/pkg/database"
+	dbfuture "github.com/solutionchallenge/ondaum-server/pkg/future/database"
+)
+
+var MigrationUser024CreateFutureScheduleTable = database.Migration{
+	Name:  "user.024.create_future_schedule_table",
+	Query: dbfuture.FutureScheduleTableCreationSQL,
+}
@@ -0,0 +1,11 @@
+package sql
+
+import (
+	"github.com/solutionchallenge/ondaum-server/pkg/database"
+	dbfuture "github.com/solutionchallenge/ondaum-server/pkg/future/database"
+)
+
+var MigrationUser025CreateFutureJobAttemptTable = database.Migration{
+	Name:  "user.025.create_future_job_attempt_table",
+	Query: dbfuture.FutureJobAttemptTableCreationSQL,
+}
@@ -0,0 +1,11 @@
+package sql
+
+import "github.com/solutionchallenge/ondaum-server/pkg/database"
+
+const sqlUser026UpdateFutureJobRow = `
+UPDATE future_jobs SET status = 'dead_lettered' WHERE status = 'failed';`
+
+var MigrationUser026UpdateFutureJobRow = database.Migration{
+	Name:  "user.026.update_future_job_row",
+	Query: sqlUser026UpdateFutureJobRow,
+}
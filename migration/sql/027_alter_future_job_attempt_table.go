@@ -0,0 +1,12 @@
+package sql
+
+import "github.com/solutionchallenge/ondaum-server/pkg/database"
+
+const sqlUser027AlterFutureJobAttemptTable = `
+ALTER TABLE future_job_attempts
+ADD COLUMN reason VARCHAR(50) NULL AFTER outcome`
+
+var MigrationUser027AlterFutureJobAttemptTable = database.Migration{
+	Name:  "user.027.alter_future_job_attempt_table",
+	Query: sqlUser027AlterFutureJobAttemptTable,
+}
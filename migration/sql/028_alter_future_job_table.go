@@ -0,0 +1,17 @@
+package sql
+
+import "github.com/solutionchallenge/ondaum-server/pkg/database"
+
+const sqlUser028AlterFutureJobTable = `
+ALTER TABLE future_jobs
+ADD COLUMN workflow_id VARCHAR(64) NULL AFTER lease_expires_at,
+ADD COLUMN parent_id BIGINT NULL AFTER workflow_id,
+ADD COLUMN depends_on JSON NULL AFTER parent_id,
+ADD COLUMN successors JSON NULL AFTER depends_on,
+ADD COLUMN output TEXT NULL AFTER successors,
+ADD INDEX idx_workflow_id (workflow_id)`
+
+var MigrationUser028AlterFutureJobTable = database.Migration{
+	Name:  "user.028.alter_future_job_table",
+	Query: sqlUser028AlterFutureJobTable,
+}
@@ -0,0 +1,13 @@
+package sql
+
+import "github.com/solutionchallenge/ondaum-server/pkg/database"
+
+const sqlUser029AlterChatHistoryTable = `
+ALTER TABLE chat_histories
+DROP INDEX idx_chat_sequence,
+ADD UNIQUE KEY unique_chat_sequence (chat_id, sequence);`
+
+var MigrationUser029AlterChatHistoryTable = database.Migration{
+	Name:  "user.029.alter_chat_history_table",
+	Query: sqlUser029AlterChatHistoryTable,
+}
@@ -3,7 +3,51 @@ package future
 import "time"
 
 type Config struct {
-	Enabled               bool          `mapstructure:"enabled"`
-	ScheduleCycle         time.Duration `mapstructure:"schedule_cycle"`
-	DeleteAfterCompletion bool          `mapstructure:"delete_after_completion"`
+	Enabled               bool                   `mapstructure:"enabled"`
+	ScheduleCycle         time.Duration          `mapstructure:"schedule_cycle"`
+	DeleteAfterCompletion bool                   `mapstructure:"delete_after_completion"`
+	Retry                 map[string]RetryPolicy `mapstructure:"retry"`
+	Worker                WorkerConfig           `mapstructure:"worker"`
+	Lease                 LeaseConfig            `mapstructure:"lease"`
+	Schedule              ScheduleConfig         `mapstructure:"schedule"`
+	Alert                 AlertConfig            `mapstructure:"alert"`
+}
+
+type WorkerConfig struct {
+	Concurrency int            `mapstructure:"concurrency" default:"4"`
+	Limits      map[string]int `mapstructure:"limits"`
+	Timeout     time.Duration  `mapstructure:"timeout" default:"10m"`
+}
+
+type LeaseConfig struct {
+	Duration          time.Duration `mapstructure:"duration" default:"1m"`
+	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval" default:"20s"`
+	ReapInterval      time.Duration `mapstructure:"reap_interval" default:"30s"`
+}
+
+type ScheduleConfig struct {
+	MisfireGrace time.Duration                 `mapstructure:"misfire_grace" default:"10m"`
+	Definitions  map[string]ScheduleDefinition `mapstructure:"definitions"`
+}
+
+type ScheduleDefinition struct {
+	ActionType   string `mapstructure:"action_type"`
+	ActionParams string `mapstructure:"action_params"`
+	Kind         string `mapstructure:"kind"`
+	Expression   string `mapstructure:"expression"`
+}
+
+type AlertConfig struct {
+	Log     bool               `mapstructure:"log" default:"true"`
+	Webhook WebhookAlertConfig `mapstructure:"webhook"`
+	Admin   AdminAlertConfig   `mapstructure:"admin"`
+}
+
+type WebhookAlertConfig struct {
+	URL     string        `mapstructure:"url"`
+	Timeout time.Duration `mapstructure:"timeout" default:"5s"`
+}
+
+type AdminAlertConfig struct {
+	Capacity int `mapstructure:"capacity" default:"100"`
 }
@@ -6,8 +6,11 @@ import (
 )
 
 type Transaction interface {
-	Complete(ctx context.Context) error
+	Complete(ctx context.Context, output string, successors []JobSpec) error
 	Fail(ctx context.Context, errorMessage string) error
+	Retry(ctx context.Context, reason FailureReason, errorMessage string, retryAfter time.Duration) error
+	Heartbeat(ctx context.Context) error
+	DeadLetter(ctx context.Context, reason FailureReason, errorMessage string) error
 }
 
 type Core interface {
@@ -17,6 +20,16 @@ type Core interface {
 	Reschdule(ctx context.Context, ID string, triggerAfter time.Duration, onlyPending bool) error
 	Inspect(ctx context.Context, ID string) (*Job, error)
 	FindBy(ctx context.Context, actionIdentifier string) (*Job, error)
-	RunNext(ctx context.Context, ignoreTriggerAfter bool) (*Job, Transaction, error)
+	RunNext(ctx context.Context, ignoreTriggerAfter bool, excludeTypes ...JobType) (*Job, Transaction, error)
 	DeletePermanently(ctx context.Context, ID string) error
+	ReapExpired(ctx context.Context, policyOf func(JobType) RetryPolicy) (int64, []Job, error)
+	List(ctx context.Context, filter JobFilter) ([]Job, int, error)
+	History(ctx context.Context, ID string) ([]JobAttempt, error)
+	Replay(ctx context.Context, ID string) (bool, error)
+	Requeue(ctx context.Context, ID string) (bool, error)
+	Enqueue(ctx context.Context, spec JobSpec) (*Job, error)
+	Await(ctx context.Context, dependsOn []string, spec JobSpec) (*Job, error)
+	ReleaseWaiting(ctx context.Context) (int64, error)
+	NextTriggerAt(ctx context.Context) (time.Time, error)
+	Wakeup() <-chan struct{}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"strconv"
 	"time"
 
@@ -16,13 +17,27 @@ import (
 
 var _ future.Core = &Core{}
 
+const (
+	ClaimRetryLimit = 5
+)
+
 type Core struct {
-	DB    *bun.DB
-	Clock clock.Clock
+	DB            *bun.DB
+	Clock         clock.Clock
+	WorkerID      string
+	LeaseDuration time.Duration
+	WakeSignal    chan struct{}
 }
 
-func NewCore(db *bun.DB, clk clock.Clock) *Core {
-	return &Core{DB: db, Clock: clk}
+func NewCore(db *bun.DB, clk clock.Clock, workerID string, leaseDuration time.Duration) *Core {
+	if leaseDuration <= 0 {
+		leaseDuration = future.DefaultLeaseDuration
+	}
+	return &Core{DB: db, Clock: clk, WorkerID: workerID, LeaseDuration: leaseDuration, WakeSignal: make(chan struct{}, 1)}
+}
+
+func (c *Core) Wakeup() <-chan struct{} {
+	return c.WakeSignal
 }
 
 func (c *Core) Create(ctx context.Context, actionType future.JobType, actionParams string, triggerAfter time.Duration, actionIdentifier ...string) (*future.Job, error) {
@@ -48,6 +63,7 @@ func (c *Core) Create(ctx context.Context, actionType future.JobType, actionPara
 	if err != nil {
 		return nil, utils.WrapError(err, "failed to get last insert id")
 	}
+	future.Signal(c.WakeSignal)
 
 	return &future.Job{
 		ID:               strconv.FormatInt(id, 10),
@@ -73,6 +89,7 @@ func (c *Core) Update(ctx context.Context, ID string, actionType future.JobType,
 	if err != nil {
 		return utils.WrapError(err, "failed to update future job")
 	}
+	future.Signal(c.WakeSignal)
 	return nil
 }
 
@@ -95,33 +112,163 @@ func (c *Core) Reschdule(ctx context.Context, ID string, triggerAfter time.Durat
 	if onlyPending {
 		query = query.Where("status = ?", future.JobStatusPending)
 	} else {
-		query = query.Set("status = ?", future.JobStatusPending)
+		query = query.
+			Set("status = ?", future.JobStatusPending).
+			Set("next_attempt_at = NULL").
+			Set("worker_id = NULL").
+			Set("lease_expires_at = NULL")
 	}
 
 	_, err := query.Where("id = ?", ID).Exec(ctx)
 	if err != nil {
 		return utils.WrapError(err, "failed to reschedule future job")
 	}
+	future.Signal(c.WakeSignal)
 	return nil
 }
 
+// Requeue refuses jobs that are still in flight and fan-in jobs whose dependencies have not all completed.
+func (c *Core) Requeue(ctx context.Context, ID string) (bool, error) {
+	job := &FutureJob{}
+	err := c.DB.NewSelect().Model(job).Column("id", "status", "depends_on").Where("id = ?", ID).Scan(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, utils.WrapError(err, "failed to get future job")
+	}
+	if !future.CanRequeue(job.Status) {
+		return false, nil
+	}
+	if len(job.DependsOn) > 0 {
+		// Dependencies deleted after completion no longer block anything.
+		outstanding, err := c.DB.NewSelect().Model((*FutureJob)(nil)).
+			Where("id IN (?)", bun.In(job.DependsOn)).
+			Where("status != ?", future.JobStatusCompleted).
+			Exists(ctx)
+		if err != nil {
+			return false, utils.WrapError(err, "failed to check future job dependencies")
+		}
+		if outstanding {
+			return false, nil
+		}
+	}
+
+	result, err := c.DB.NewUpdate().Model((*FutureJob)(nil)).
+		Set("status = ?", future.JobStatusPending).
+		Set("triggered_at = ?", c.Clock.Now().UTC()).
+		Set("next_attempt_at = NULL").
+		Set("completed_at = NULL").
+		Set("worker_id = NULL").
+		Set("lease_expires_at = NULL").
+		Where("id = ?", ID).
+		Where("status = ?", job.Status).
+		Exec(ctx)
+	if err != nil {
+		return false, utils.WrapError(err, "failed to requeue future job")
+	}
+	requeued, err := result.RowsAffected()
+	if err != nil {
+		return false, utils.WrapError(err, "failed to get requeued rows")
+	}
+	if requeued > 0 {
+		future.Signal(c.WakeSignal)
+	}
+	return requeued > 0, nil
+}
+
+func (c *Core) Replay(ctx context.Context, ID string) (bool, error) {
+	result, err := c.DB.NewUpdate().Model((*FutureJob)(nil)).
+		Set("status = ?", future.JobStatusPending).
+		Set("triggered_at = ?", c.Clock.Now().UTC()).
+		Set("attempts = 0").
+		Set("next_attempt_at = NULL").
+		Set("completed_at = NULL").
+		Set("worker_id = NULL").
+		Set("error = NULL").
+		Where("id = ?", ID).
+		Where("status = ?", future.JobStatusDeadLettered).
+		Exec(ctx)
+	if err != nil {
+		return false, utils.WrapError(err, "failed to replay future job")
+	}
+	replayed, err := result.RowsAffected()
+	if err != nil {
+		return false, utils.WrapError(err, "failed to get replayed rows")
+	}
+	if replayed > 0 {
+		future.Signal(c.WakeSignal)
+	}
+	return replayed > 0, nil
+}
+
 func (c *Core) Inspect(ctx context.Context, ID string) (*future.Job, error) {
 	var job FutureJob
 	err := c.DB.NewSelect().Model(&job).Where("id = ?", ID).Scan(ctx)
 	if err != nil {
 		return nil, utils.WrapError(err, "failed to inspect future job")
 	}
+	inspected := toJob(&job)
+	if job.WorkflowID != "" {
+		inspected.Workflow, err = c.inspectWorkflow(ctx, job.WorkflowID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return inspected, nil
+}
 
-	return &future.Job{
-		ID:               strconv.FormatInt(job.ID, 10),
-		ActionIdentifier: job.ActionIdentifier,
-		ActionType:       job.ActionType,
-		ActionParams:     job.ActionParams,
-		TriggeredAt:      job.TriggeredAt,
-		CompletedAt:      job.CompletedAt,
-		Status:           job.Status,
-		Error:            job.Error,
-	}, nil
+func (c *Core) List(ctx context.Context, filter future.JobFilter) ([]future.Job, int, error) {
+	var jobs []FutureJob
+	query := c.DB.NewSelect().Model(&jobs).
+		Order("triggered_at DESC").
+		Order("id DESC")
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ActionType != "" {
+		query = query.Where("action_type = ?", filter.ActionType)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("triggered_at >= ?", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("triggered_at <= ?", filter.To.UTC())
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit).Offset(filter.Offset)
+	}
+
+	total, err := query.ScanAndCount(ctx)
+	if err != nil {
+		return nil, 0, utils.WrapError(err, "failed to list future jobs")
+	}
+	return utils.Map(jobs, func(job FutureJob) future.Job {
+		return *toJob(&job)
+	}), total, nil
+}
+
+func (c *Core) History(ctx context.Context, ID string) ([]future.JobAttempt, error) {
+	var attempts []FutureJobAttempt
+	err := c.DB.NewSelect().Model(&attempts).
+		Where("job_id = ?", ID).
+		Order("id ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to get future job history")
+	}
+	return utils.Map(attempts, func(attempt FutureJobAttempt) future.JobAttempt {
+		return future.JobAttempt{
+			Attempt:    attempt.Attempt,
+			WorkerID:   attempt.WorkerID,
+			Outcome:    attempt.Outcome,
+			Reason:     attempt.Reason,
+			Error:      attempt.Error,
+			StartedAt:  attempt.StartedAt,
+			FinishedAt: attempt.FinishedAt,
+		}
+	}), nil
 }
 
 func (c *Core) FindBy(ctx context.Context, actionIdentifier string) (*future.Job, error) {
@@ -141,67 +288,181 @@ func (c *Core) FindBy(ctx context.Context, actionIdentifier string) (*future.Job
 	}, nil
 }
 
-func (c *Core) RunNext(ctx context.Context, ignoreTriggerAfter bool) (*future.Job, future.Transaction, error) {
-	var job FutureJob
+func (c *Core) RunNext(ctx context.Context, ignoreTriggerAfter bool, excludeTypes ...future.JobType) (*future.Job, future.Transaction, error) {
+	for range ClaimRetryLimit {
+		var job FutureJob
 
-	tx, err := c.DB.BeginTx(ctx, nil)
-	if err != nil {
-		return nil, nil, utils.WrapError(err, "failed to begin transaction")
-	}
+		query := c.DB.NewSelect().Model(&job).
+			Where("status = ?", future.JobStatusPending).
+			Order("triggered_at ASC").
+			Limit(1)
 
-	query := tx.NewSelect().Model(&job).
-		Where("status = ?", future.JobStatusPending).
-		Order("triggered_at ASC").
-		Limit(1)
+		if !ignoreTriggerAfter {
+			now := c.Clock.Now().UTC()
+			query = query.
+				Where("triggered_at <= ?", now).
+				Where("(next_attempt_at IS NULL OR next_attempt_at <= ?)", now)
+		}
+
+		if len(excludeTypes) > 0 {
+			query = query.Where("action_type NOT IN (?)", bun.In(excludeTypes))
+		}
 
-	if !ignoreTriggerAfter {
-		query = query.Where("triggered_at <= ?", c.Clock.Now().UTC())
+		err := query.Scan(ctx)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, nil, nil
+			}
+			return nil, nil, utils.WrapError(err, "failed to scan future job")
+		}
+
+		leaseExpiresAt := c.Clock.Now().UTC().Add(c.LeaseDuration)
+		result, err := c.DB.NewUpdate().Model((*FutureJob)(nil)).
+			Set("status = ?", future.JobStatusRunning).
+			Set("attempts = attempts + 1").
+			Set("worker_id = ?", c.WorkerID).
+			Set("lease_expires_at = ?", leaseExpiresAt).
+			Where("id = ?", job.ID).
+			Where("status = ?", future.JobStatusPending).
+			Exec(ctx)
+		if err != nil {
+			return nil, nil, utils.WrapError(err, "failed to claim future job")
+		}
+		claimed, err := result.RowsAffected()
+		if err != nil {
+			return nil, nil, utils.WrapError(err, "failed to get claimed rows")
+		}
+		if claimed == 0 {
+			utils.Log(utils.DebugLevel).BT().Send("Future job %d was claimed by another worker", job.ID)
+			continue
+		}
+
+		job.Status = future.JobStatusRunning
+		job.Attempts++
+		job.WorkerID = c.WorkerID
+		job.LeaseExpiresAt = leaseExpiresAt
+
+		jobTx := &Transaction{
+			DB:            c.DB,
+			Job:           &job,
+			Clock:         c.Clock,
+			LeaseDuration: c.LeaseDuration,
+			StartedAt:     c.Clock.Now().UTC(),
+		}
+
+		return toJob(&job), jobTx, nil
 	}
+	return nil, nil, nil
+}
 
-	err = query.Scan(ctx)
+func (c *Core) ReapExpired(ctx context.Context, policyOf func(future.JobType) future.RetryPolicy) (int64, []future.Job, error) {
+	now := c.Clock.Now().UTC()
+	expired := []FutureJob{}
+	err := c.DB.NewSelect().Model(&expired).
+		Where("status = ?", future.JobStatusRunning).
+		Where("(lease_expires_at IS NULL OR lease_expires_at < ?)", now).
+		Scan(ctx)
 	if err != nil {
-		tx.Rollback()
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, nil, nil
+		return 0, nil, utils.WrapError(err, "failed to find expired future jobs")
+	}
+
+	reaped := int64(0)
+	deadLettered := []future.Job{}
+	for i := range expired {
+		job := &expired[i]
+		exhausted := !policyOf(job.ActionType).ShouldRetry(job.Attempts)
+		errorMessage := fmt.Sprintf("lease of %s expired on attempt %d", job.WorkerID, job.Attempts)
+		update := c.DB.NewUpdate().Model((*FutureJob)(nil)).
+			Set("worker_id = NULL").
+			Set("lease_expires_at = NULL").
+			Where("id = ?", job.ID).
+			Where("status = ?", future.JobStatusRunning).
+			Where("attempts = ?", job.Attempts).
+			Where("(lease_expires_at IS NULL OR lease_expires_at < ?)", now)
+		if exhausted {
+			update = update.
+				Set("status = ?", future.JobStatusDeadLettered).
+				Set("error = ?", errorMessage).
+				Set("completed_at = ?", now)
+		} else {
+			update = update.Set("status = ?", future.JobStatusPending)
 		}
-		return nil, nil, utils.WrapError(err, "failed to scan future job")
+		result, err := update.Exec(ctx)
+		if err != nil {
+			return reaped, deadLettered, utils.WrapError(err, "failed to reap expired future job %d", job.ID)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return reaped, deadLettered, utils.WrapError(err, "failed to get reaped rows")
+		}
+		if affected == 0 {
+			continue
+		}
+		reaped++
+		if !exhausted {
+			continue
+		}
+		jobTx := &Transaction{DB: c.DB, Job: job, Clock: c.Clock, StartedAt: now}
+		jobTx.record(ctx, future.JobStatusDeadLettered, future.FailureReasonLease, errorMessage)
+		job.Status = future.JobStatusDeadLettered
+		job.Error = errorMessage
+		job.CompletedAt = now
+		deadLettered = append(deadLettered, *toJob(job))
 	}
+	if reaped > 0 {
+		future.Signal(c.WakeSignal)
+	}
+	return reaped, deadLettered, nil
+}
 
-	_, err = tx.NewUpdate().Model(&job).
-		Set("status = ?", future.JobStatusRunning).
-		Where("id = ?", job.ID).
-		Exec(ctx)
+func (c *Core) NextTriggerAt(ctx context.Context) (time.Time, error) {
+	var next sql.NullTime
+	err := c.DB.NewSelect().Model((*FutureJob)(nil)).
+		ColumnExpr("MIN(CASE WHEN next_attempt_at > triggered_at THEN next_attempt_at ELSE triggered_at END)").
+		Where("status = ?", future.JobStatusPending).
+		Scan(ctx, &next)
 	if err != nil {
-		tx.Rollback()
-		return nil, nil, utils.WrapError(err, "failed to update future job status")
+		return time.Time{}, utils.WrapError(err, "failed to get next trigger of future jobs")
 	}
+	return next.Time, nil
+}
 
-	err = tx.Commit()
+func (c *Core) DeletePermanently(ctx context.Context, ID string) error {
+	_, err := c.DB.NewDelete().Model((*FutureJob)(nil)).Where("id = ?", ID).Exec(ctx)
 	if err != nil {
-		tx.Rollback()
-		return nil, nil, utils.WrapError(err, "failed to commit transaction")
+		return utils.WrapError(err, "failed to delete future job")
 	}
-
-	jobTx := &Transaction{
-		DB:    c.DB,
-		Job:   &job,
-		Clock: c.Clock,
+	_, err = c.DB.NewDelete().Model((*FutureJobAttempt)(nil)).Where("job_id = ?", ID).Exec(ctx)
+	if err != nil {
+		return utils.WrapError(err, "failed to delete future job history")
 	}
+	return nil
+}
 
+func toJob(job *FutureJob) *future.Job {
+	parentID := ""
+	if job.ParentID != 0 {
+		parentID = strconv.FormatInt(job.ParentID, 10)
+	}
 	return &future.Job{
 		ID:               strconv.FormatInt(job.ID, 10),
 		ActionIdentifier: job.ActionIdentifier,
 		ActionType:       job.ActionType,
 		ActionParams:     job.ActionParams,
 		TriggeredAt:      job.TriggeredAt,
+		CompletedAt:      job.CompletedAt,
 		Status:           job.Status,
-	}, jobTx, nil
-}
-
-func (c *Core) DeletePermanently(ctx context.Context, ID string) error {
-	_, err := c.DB.NewDelete().Model((*FutureJob)(nil)).Where("id = ?", ID).Exec(ctx)
-	if err != nil {
-		return utils.WrapError(err, "failed to delete future job")
+		Error:            job.Error,
+		Attempts:         job.Attempts,
+		NextAttemptAt:    job.NextAttemptAt,
+		WorkerID:         job.WorkerID,
+		LeaseExpiresAt:   job.LeaseExpiresAt,
+		WorkflowID:       job.WorkflowID,
+		ParentID:         parentID,
+		DependsOn:        job.DependsOn,
+		Successors:       job.Successors,
+		Output:           job.Output,
+		CreatedAt:        job.CreatedAt,
+		UpdatedAt:        job.UpdatedAt,
 	}
-	return nil
 }
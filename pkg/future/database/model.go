@@ -14,8 +14,17 @@ type FutureJob struct {
 	ActionType       future.JobType   `bun:"action_type,notnull"`
 	ActionParams     string           `bun:"action_params,notnull"`
 	TriggeredAt      time.Time        `bun:"triggered_at,notnull"`
+	NextAttemptAt    time.Time        `bun:"next_attempt_at,nullzero"`
+	Attempts         int              `bun:"attempts,notnull,default:0"`
 	CompletedAt      time.Time        `bun:"completed_at"`
 	Status           future.JobStatus `bun:"status,notnull"`
+	WorkerID         string           `bun:"worker_id,nullzero"`
+	LeaseExpiresAt   time.Time        `bun:"lease_expires_at,nullzero"`
+	WorkflowID       string           `bun:"workflow_id,nullzero"`
+	ParentID         int64            `bun:"parent_id,nullzero"`
+	DependsOn        []string         `bun:"depends_on,type:json,nullzero"`
+	Successors       []future.JobSpec `bun:"successors,type:json,nullzero"`
+	Output           string           `bun:"output"`
 	Error            string           `bun:"error"`
 	CreatedAt        time.Time        `bun:"created_at,notnull,default:CURRENT_TIMESTAMP"`
 	UpdatedAt        time.Time        `bun:"updated_at,notnull,default:CURRENT_TIMESTAMP"`
@@ -35,3 +44,63 @@ CREATE TABLE IF NOT EXISTS future_jobs (
 	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
 )
 `
+
+type FutureSchedule struct {
+	bun.BaseModel `bun:"future_schedules"`
+	ID            int64               `bun:"id,pk,autoincrement"`
+	Name          string              `bun:"name,notnull"`
+	ActionType    future.JobType      `bun:"action_type,notnull"`
+	ActionParams  string              `bun:"action_params,notnull"`
+	Kind          future.ScheduleKind `bun:"kind,notnull"`
+	Expression    string              `bun:"expression,notnull"`
+	Enabled       bool                `bun:"enabled,notnull"`
+	NextRunAt     time.Time           `bun:"next_run_at,notnull"`
+	LastRunAt     time.Time           `bun:"last_run_at,nullzero"`
+	CreatedAt     time.Time           `bun:"created_at,notnull,default:CURRENT_TIMESTAMP"`
+	UpdatedAt     time.Time           `bun:"updated_at,notnull,default:CURRENT_TIMESTAMP"`
+}
+
+const FutureScheduleTableCreationSQL = `
+CREATE TABLE IF NOT EXISTS future_schedules (
+	id BIGINT PRIMARY KEY AUTO_INCREMENT,
+	name VARCHAR(255) NOT NULL,
+	action_type VARCHAR(255) NOT NULL,
+	action_params TEXT NOT NULL,
+	kind VARCHAR(50) NOT NULL,
+	expression VARCHAR(255) NOT NULL,
+	enabled BOOLEAN NOT NULL DEFAULT TRUE,
+	next_run_at DATETIME NOT NULL,
+	last_run_at DATETIME,
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE INDEX idx_name (name),
+	INDEX idx_next_run_at (next_run_at)
+)
+`
+
+type FutureJobAttempt struct {
+	bun.BaseModel `bun:"future_job_attempts"`
+	ID            int64                `bun:"id,pk,autoincrement"`
+	JobID         int64                `bun:"job_id,notnull"`
+	Attempt       int                  `bun:"attempt,notnull"`
+	WorkerID      string               `bun:"worker_id,notnull"`
+	Outcome       future.JobStatus     `bun:"outcome,notnull"`
+	Reason        future.FailureReason `bun:"reason,nullzero"`
+	Error         string               `bun:"error"`
+	StartedAt     time.Time            `bun:"started_at,notnull"`
+	FinishedAt    time.Time            `bun:"finished_at,notnull"`
+}
+
+const FutureJobAttemptTableCreationSQL = `
+CREATE TABLE IF NOT EXISTS future_job_attempts (
+	id BIGINT PRIMARY KEY AUTO_INCREMENT,
+	job_id BIGINT NOT NULL,
+	attempt INT NOT NULL,
+	worker_id VARCHAR(255) NOT NULL,
+	outcome VARCHAR(50) NOT NULL,
+	error TEXT,
+	started_at DATETIME NOT NULL,
+	finished_at DATETIME NOT NULL,
+	INDEX idx_job_id (job_id)
+)
+`
@@ -0,0 +1,228 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	"github.com/uptrace/bun"
+)
+
+var _ future.ScheduleCore = &Core{}
+
+func (c *Core) RegisterSchedule(ctx context.Context, name string, actionType future.JobType, actionParams string, kind future.ScheduleKind, expression string) (*future.Schedule, error) {
+	now := c.Clock.Now().UTC()
+	nextRunAt, err := future.NextRun(kind, expression, now)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to compute next run for schedule %v", name)
+	}
+
+	schedule := &FutureSchedule{}
+	err = c.DB.NewSelect().Model(schedule).Where("name = ?", name).Scan(ctx)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, utils.WrapError(err, "failed to get future schedule %v", name)
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		schedule = &FutureSchedule{
+			Name:         name,
+			ActionType:   actionType,
+			ActionParams: actionParams,
+			Kind:         kind,
+			Expression:   expression,
+			Enabled:      true,
+			NextRunAt:    nextRunAt,
+		}
+		if _, err := c.DB.NewInsert().Model(schedule).Exec(ctx); err != nil {
+			return nil, utils.WrapError(err, "failed to create future schedule %v", name)
+		}
+		future.Signal(c.WakeSignal)
+		return toSchedule(schedule), nil
+	}
+
+	update := c.DB.NewUpdate().Model(schedule).
+		Set("action_type = ?", actionType).
+		Set("action_params = ?", actionParams).
+		Set("updated_at = ?", now).
+		WherePK()
+	if schedule.Kind != kind || schedule.Expression != expression {
+		update = update.
+			Set("kind = ?", kind).
+			Set("expression = ?", expression).
+			Set("next_run_at = ?", nextRunAt)
+		schedule.Kind = kind
+		schedule.Expression = expression
+		schedule.NextRunAt = nextRunAt
+	}
+	if _, err := update.Exec(ctx); err != nil {
+		return nil, utils.WrapError(err, "failed to update future schedule %v", name)
+	}
+	future.Signal(c.WakeSignal)
+	schedule.ActionType = actionType
+	schedule.ActionParams = actionParams
+	return toSchedule(schedule), nil
+}
+
+func (c *Core) EnableSchedule(ctx context.Context, name string, enabled bool) error {
+	schedule := &FutureSchedule{}
+	if err := c.DB.NewSelect().Model(schedule).Where("name = ?", name).Scan(ctx); err != nil {
+		return utils.WrapError(err, "failed to get future schedule %v", name)
+	}
+
+	update := c.DB.NewUpdate().Model(schedule).
+		Set("enabled = ?", enabled).
+		Set("updated_at = ?", c.Clock.Now().UTC()).
+		WherePK()
+	if enabled && !schedule.Enabled {
+		// Resume from now instead of catching up on the runs missed while disabled.
+		nextRunAt, err := future.NextRun(schedule.Kind, schedule.Expression, c.Clock.Now().UTC())
+		if err != nil {
+			return utils.WrapError(err, "failed to compute next run for schedule %v", name)
+		}
+		update = update.Set("next_run_at = ?", nextRunAt)
+	}
+	if _, err := update.Exec(ctx); err != nil {
+		return utils.WrapError(err, "failed to toggle future schedule %v", name)
+	}
+	future.Signal(c.WakeSignal)
+	return nil
+}
+
+func (c *Core) ListSchedules(ctx context.Context) ([]future.Schedule, error) {
+	var schedules []FutureSchedule
+	if err := c.DB.NewSelect().Model(&schedules).Order("name ASC").Scan(ctx); err != nil {
+		return nil, utils.WrapError(err, "failed to list future schedules")
+	}
+	result := make([]future.Schedule, 0, len(schedules))
+	for i := range schedules {
+		result = append(result, *toSchedule(&schedules[i]))
+	}
+	return result, nil
+}
+
+func (c *Core) DeleteSchedule(ctx context.Context, name string) error {
+	_, err := c.DB.NewDelete().Model((*FutureSchedule)(nil)).Where("name = ?", name).Exec(ctx)
+	if err != nil {
+		return utils.WrapError(err, "failed to delete future schedule %v", name)
+	}
+	return nil
+}
+
+func (c *Core) MaterializeSchedules(ctx context.Context, misfireGrace time.Duration) (int, error) {
+	now := c.Clock.Now().UTC()
+
+	var due []FutureSchedule
+	err := c.DB.NewSelect().Model(&due).
+		Where("enabled = ?", true).
+		Where("next_run_at <= ?", now).
+		Order("next_run_at ASC").
+		Scan(ctx)
+	if err != nil {
+		return 0, utils.WrapError(err, "failed to get due future schedules")
+	}
+
+	created := 0
+	for _, schedule := range due {
+		materialized, err := c.materializeSchedule(ctx, schedule.ID, now, misfireGrace)
+		if err != nil {
+			return created, err
+		}
+		if materialized {
+			created++
+		}
+	}
+	if created > 0 {
+		future.Signal(c.WakeSignal)
+	}
+	return created, nil
+}
+
+// materializeSchedule advances one schedule and creates its job in the same transaction, so a crash in between
+// can neither skip a run nor repeat it.
+func (c *Core) materializeSchedule(ctx context.Context, id int64, now time.Time, misfireGrace time.Duration) (bool, error) {
+	materialized := false
+	err := c.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		schedule := &FutureSchedule{}
+		err := tx.NewSelect().Model(schedule).
+			Where("id = ?", id).
+			Where("enabled = ?", true).
+			Where("next_run_at <= ?", now).
+			For("UPDATE").
+			Scan(ctx)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Another replica got here first.
+			return nil
+		}
+		if err != nil {
+			return utils.WrapError(err, "failed to lock future schedule %d", id)
+		}
+
+		// Missed runs collapse into a single run: the next one is always computed from now.
+		nextRunAt, err := future.NextRun(schedule.Kind, schedule.Expression, now)
+		if err != nil {
+			utils.Log(utils.WarnLevel).Err(err).BT().Send("Skipping schedule %v with invalid expression", schedule.Name)
+			return nil
+		}
+		_, err = tx.NewUpdate().Model((*FutureSchedule)(nil)).
+			Set("next_run_at = ?", nextRunAt).
+			Set("last_run_at = ?", now).
+			Where("id = ?", schedule.ID).
+			Exec(ctx)
+		if err != nil {
+			return utils.WrapError(err, "failed to advance future schedule %v", schedule.Name)
+		}
+
+		if misfireGrace > 0 && now.Sub(schedule.NextRunAt) > misfireGrace {
+			utils.Log(utils.InfoLevel).BT().Send("Skipping run of schedule %v missed at %v", schedule.Name, schedule.NextRunAt)
+			return nil
+		}
+		job := &FutureJob{
+			ActionIdentifier: future.ScheduledIdentifier(schedule.Name, schedule.NextRunAt),
+			ActionType:       schedule.ActionType,
+			ActionParams:     schedule.ActionParams,
+			TriggeredAt:      now,
+			Status:           future.JobStatusPending,
+		}
+		if _, err := tx.NewInsert().Model(job).Exec(ctx); err != nil {
+			return utils.WrapError(err, "failed to materialize future schedule %v", schedule.Name)
+		}
+		materialized = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return materialized, nil
+}
+
+func (c *Core) NextScheduledAt(ctx context.Context) (time.Time, error) {
+	var next sql.NullTime
+	err := c.DB.NewSelect().Model((*FutureSchedule)(nil)).
+		ColumnExpr("MIN(next_run_at)").
+		Where("enabled = ?", true).
+		Scan(ctx, &next)
+	if err != nil {
+		return time.Time{}, utils.WrapError(err, "failed to get next run of future schedules")
+	}
+	return next.Time, nil
+}
+
+func toSchedule(schedule *FutureSchedule) *future.Schedule {
+	return &future.Schedule{
+		ID:           strconv.FormatInt(schedule.ID, 10),
+		Name:         schedule.Name,
+		ActionType:   schedule.ActionType,
+		ActionParams: schedule.ActionParams,
+		Kind:         schedule.Kind,
+		Expression:   schedule.Expression,
+		Enabled:      schedule.Enabled,
+		NextRunAt:    schedule.NextRunAt,
+		LastRunAt:    schedule.LastRunAt,
+		CreatedAt:    schedule.CreatedAt,
+		UpdatedAt:    schedule.UpdatedAt,
+	}
+}
@@ -2,40 +2,178 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/benbjohnson/clock"
+	"github.com/google/uuid"
 	"github.com/solutionchallenge/ondaum-server/pkg/future"
 	"github.com/solutionchallenge/ondaum-server/pkg/utils"
 	"github.com/uptrace/bun"
 )
 
 type Transaction struct {
-	DB    *bun.DB
-	Job   *FutureJob
-	Clock clock.Clock
+	DB            *bun.DB
+	Job           *FutureJob
+	Clock         clock.Clock
+	LeaseDuration time.Duration
+	StartedAt     time.Time
 }
 
-func (t *Transaction) Complete(ctx context.Context) error {
-	_, err := t.DB.NewUpdate().Model(t.Job).
-		Set("status = ?", future.JobStatusCompleted).
-		Set("completed_at = ?", t.Clock.Now().UTC()).
-		Where("id = ?", t.Job.ID).
-		Exec(ctx)
+func (t *Transaction) Complete(ctx context.Context, output string, successors []future.JobSpec) error {
+	now := t.Clock.Now().UTC()
+	workflowID := t.Job.WorkflowID
+	if workflowID == "" && len(successors) > 0 {
+		workflowID = uuid.New().String()
+	}
+
+	err := t.DB.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		update := tx.NewUpdate().Model(t.Job).
+			Set("status = ?", future.JobStatusCompleted).
+			Set("completed_at = ?", now).
+			Set("output = ?", output).
+			Set("lease_expires_at = NULL")
+		if workflowID != "" {
+			update = update.Set("workflow_id = ?", workflowID)
+		}
+		result, err := t.owned(update).Exec(ctx)
+		if err != nil {
+			return utils.WrapError(err, "failed to complete future job")
+		}
+		if err := t.checkLease(result); err != nil {
+			return err
+		}
+		if len(successors) == 0 {
+			return nil
+		}
+
+		jobs := make([]*FutureJob, 0, len(successors))
+		for _, spec := range successors {
+			job := newSpecJob(spec, output, workflowID, now)
+			job.ParentID = t.Job.ID
+			jobs = append(jobs, job)
+		}
+		if _, err := tx.NewInsert().Model(&jobs).Exec(ctx); err != nil {
+			return utils.WrapError(err, "failed to create successors of future job")
+		}
+		return nil
+	})
 	if err != nil {
-		return utils.WrapError(err, "failed to complete future job")
+		return err
 	}
+	t.record(ctx, future.JobStatusCompleted, "", "")
 	return nil
 }
 
 func (t *Transaction) Fail(ctx context.Context, errorMessage string) error {
-	_, err := t.DB.NewUpdate().Model(t.Job).
+	result, err := t.owned(t.DB.NewUpdate().Model(t.Job).
 		Set("status = ?", future.JobStatusFailed).
 		Set("error = ?", errorMessage).
 		Set("completed_at = ?", t.Clock.Now().UTC()).
-		Where("id = ?", t.Job.ID).
+		Set("lease_expires_at = NULL")).
 		Exec(ctx)
 	if err != nil {
 		return utils.WrapError(err, "failed to fail future job")
 	}
+	if err := t.checkLease(result); err != nil {
+		return err
+	}
+	t.record(ctx, future.JobStatusFailed, future.FailureReasonError, errorMessage)
 	return nil
 }
+
+func (t *Transaction) Retry(ctx context.Context, reason future.FailureReason, errorMessage string, retryAfter time.Duration) error {
+	result, err := t.owned(t.DB.NewUpdate().Model(t.Job).
+		Set("status = ?", future.JobStatusPending).
+		Set("error = ?", errorMessage).
+		Set("next_attempt_at = ?", t.Clock.Now().UTC().Add(retryAfter)).
+		Set("worker_id = NULL").
+		Set("lease_expires_at = NULL")).
+		Exec(ctx)
+	if err != nil {
+		return utils.WrapError(err, "failed to retry future job")
+	}
+	if err := t.checkLease(result); err != nil {
+		return err
+	}
+	t.record(ctx, future.JobStatusPending, reason, errorMessage)
+	return nil
+}
+
+func (t *Transaction) DeadLetter(ctx context.Context, reason future.FailureReason, errorMessage string) error {
+	result, err := t.owned(t.DB.NewUpdate().Model(t.Job).
+		Set("status = ?", future.JobStatusDeadLettered).
+		Set("error = ?", errorMessage).
+		Set("completed_at = ?", t.Clock.Now().UTC()).
+		Set("lease_expires_at = NULL")).
+		Exec(ctx)
+	if err != nil {
+		return utils.WrapError(err, "failed to dead-letter future job")
+	}
+	if err := t.checkLease(result); err != nil {
+		return err
+	}
+	t.record(ctx, future.JobStatusDeadLettered, reason, errorMessage)
+	return nil
+}
+
+func (t *Transaction) Heartbeat(ctx context.Context) error {
+	leaseExpiresAt := t.Clock.Now().UTC().Add(t.LeaseDuration)
+	result, err := t.owned(t.DB.NewUpdate().Model(t.Job).
+		Set("lease_expires_at = ?", leaseExpiresAt)).
+		Exec(ctx)
+	if err != nil {
+		return utils.WrapError(err, "failed to extend future job lease")
+	}
+	if err := t.checkLease(result); err != nil {
+		// MySQL reports changed rows, so an unchanged lease looks the same as a lost one.
+		owned, existsErr := t.DB.NewSelect().
+			Model((*FutureJob)(nil)).
+			Where("id = ?", t.Job.ID).
+			Where("status = ?", future.JobStatusRunning).
+			Where("worker_id = ?", t.Job.WorkerID).
+			Exists(ctx)
+		if existsErr != nil {
+			return utils.WrapError(existsErr, "failed to check future job lease")
+		}
+		if !owned {
+			return err
+		}
+	}
+	t.Job.LeaseExpiresAt = leaseExpiresAt
+	return nil
+}
+
+func (t *Transaction) owned(query *bun.UpdateQuery) *bun.UpdateQuery {
+	return query.
+		Where("id = ?", t.Job.ID).
+		Where("status = ?", future.JobStatusRunning).
+		Where("worker_id = ?", t.Job.WorkerID)
+}
+
+func (t *Transaction) checkLease(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return utils.WrapError(err, "failed to get affected rows")
+	}
+	if affected == 0 {
+		return utils.WrapError(future.ErrLeaseLost, "future job %d is no longer owned by %s", t.Job.ID, t.Job.WorkerID)
+	}
+	return nil
+}
+
+func (t *Transaction) record(ctx context.Context, outcome future.JobStatus, reason future.FailureReason, errorMessage string) {
+	attempt := &FutureJobAttempt{
+		JobID:      t.Job.ID,
+		Attempt:    t.Job.Attempts,
+		WorkerID:   t.Job.WorkerID,
+		Outcome:    outcome,
+		Reason:     reason,
+		Error:      errorMessage,
+		StartedAt:  t.StartedAt,
+		FinishedAt: t.Clock.Now().UTC(),
+	}
+	if _, err := t.DB.NewInsert().Model(attempt).Exec(ctx); err != nil {
+		utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to record attempt %d of future job %d", t.Job.Attempts, t.Job.ID)
+	}
+}
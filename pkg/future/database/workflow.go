@@ -0,0 +1,205 @@
+package database
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	"github.com/uptrace/bun"
+)
+
+func (c *Core) Enqueue(ctx context.Context, spec future.JobSpec) (*future.Job, error) {
+	job := newSpecJob(spec, "", uuid.New().String(), c.Clock.Now().UTC())
+	if _, err := c.DB.NewInsert().Model(job).Exec(ctx); err != nil {
+		return nil, utils.WrapError(err, "failed to enqueue future job")
+	}
+	future.Signal(c.WakeSignal)
+	return toJob(job), nil
+}
+
+func (c *Core) Await(ctx context.Context, dependsOn []string, spec future.JobSpec) (*future.Job, error) {
+	if len(dependsOn) == 0 {
+		return nil, utils.NewError("fan-in job requires at least one dependency")
+	}
+	dependsOn = slices.Compact(slices.Sorted(slices.Values(dependsOn)))
+
+	var dependencies []FutureJob
+	err := c.DB.NewSelect().Model(&dependencies).
+		Column("id", "workflow_id").
+		Where("id IN (?)", bun.In(dependsOn)).
+		Order("id ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to get future job dependencies")
+	}
+	if len(dependencies) != len(dependsOn) {
+		return nil, utils.NewError("some of future job dependencies do not exist: %v", dependsOn)
+	}
+
+	workflowID := ""
+	for _, dependency := range dependencies {
+		if dependency.WorkflowID != "" {
+			workflowID = dependency.WorkflowID
+			break
+		}
+	}
+	if workflowID == "" {
+		workflowID = uuid.New().String()
+	}
+	_, err = c.DB.NewUpdate().Model((*FutureJob)(nil)).
+		Set("workflow_id = ?", workflowID).
+		Where("id IN (?)", bun.In(dependsOn)).
+		Where("workflow_id IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to join future job dependencies to workflow %v", workflowID)
+	}
+
+	job := newSpecJob(spec, "", workflowID, c.Clock.Now().UTC())
+	job.Status = future.JobStatusWaiting
+	job.DependsOn = dependsOn
+	if _, err := c.DB.NewInsert().Model(job).Exec(ctx); err != nil {
+		return nil, utils.WrapError(err, "failed to create fan-in future job")
+	}
+
+	if _, err := c.ReleaseWaiting(ctx); err != nil {
+		return nil, utils.WrapError(err, "failed to release fan-in future job")
+	}
+	return c.Inspect(ctx, strconv.FormatInt(job.ID, 10))
+}
+
+func (c *Core) ReleaseWaiting(ctx context.Context) (int64, error) {
+	released := int64(0)
+	for {
+		passReleased, abandoned, err := c.releaseWaiting(ctx)
+		released += passReleased
+		if err != nil {
+			return released, err
+		}
+		// Canceling a waiter can abandon the waiters depending on it in turn.
+		if abandoned == 0 {
+			break
+		}
+	}
+	if released > 0 {
+		future.Signal(c.WakeSignal)
+	}
+	return released, nil
+}
+
+func (c *Core) releaseWaiting(ctx context.Context) (int64, int64, error) {
+	var waiting []FutureJob
+	err := c.DB.NewSelect().Model(&waiting).
+		Column("id", "depends_on").
+		Where("status = ?", future.JobStatusWaiting).
+		Scan(ctx)
+	if err != nil {
+		return 0, 0, utils.WrapError(err, "failed to get waiting future jobs")
+	}
+	if len(waiting) == 0 {
+		return 0, 0, nil
+	}
+
+	dependencyIDs := []string{}
+	for _, job := range waiting {
+		dependencyIDs = append(dependencyIDs, job.DependsOn...)
+	}
+	var dependencies []FutureJob
+	err = c.DB.NewSelect().Model(&dependencies).
+		Column("id", "status").
+		Where("id IN (?)", bun.In(dependencyIDs)).
+		Where("status != ?", future.JobStatusCompleted).
+		Scan(ctx)
+	if err != nil {
+		return 0, 0, utils.WrapError(err, "failed to get incomplete future job dependencies")
+	}
+	// Dependencies deleted after completion no longer block anything.
+	incomplete := make(map[string]future.JobStatus, len(dependencies))
+	for _, dependency := range dependencies {
+		incomplete[strconv.FormatInt(dependency.ID, 10)] = dependency.Status
+	}
+
+	ready := []int64{}
+	abandoned := int64(0)
+	for _, job := range waiting {
+		blocker := slices.IndexFunc(job.DependsOn, func(ID string) bool { return future.BlocksDependents(incomplete[ID]) })
+		if blocker >= 0 {
+			canceled, err := c.abandonWaiting(ctx, &job, job.DependsOn[blocker], incomplete[job.DependsOn[blocker]])
+			if err != nil {
+				return 0, abandoned, err
+			}
+			abandoned += canceled
+			continue
+		}
+		if !slices.ContainsFunc(job.DependsOn, func(ID string) bool { _, ok := incomplete[ID]; return ok }) {
+			ready = append(ready, job.ID)
+		}
+	}
+	if len(ready) == 0 {
+		return 0, abandoned, nil
+	}
+
+	result, err := c.DB.NewUpdate().Model((*FutureJob)(nil)).
+		Set("status = ?", future.JobStatusPending).
+		Where("id IN (?)", bun.In(ready)).
+		Where("status = ?", future.JobStatusWaiting).
+		Exec(ctx)
+	if err != nil {
+		return 0, abandoned, utils.WrapError(err, "failed to release waiting future jobs")
+	}
+	released, err := result.RowsAffected()
+	if err != nil {
+		return 0, abandoned, utils.WrapError(err, "failed to get released rows")
+	}
+	return released, abandoned, nil
+}
+
+func (c *Core) abandonWaiting(ctx context.Context, job *FutureJob, dependencyID string, status future.JobStatus) (int64, error) {
+	errorMessage := future.AbandonedError(dependencyID, status)
+	result, err := c.DB.NewUpdate().Model((*FutureJob)(nil)).
+		Set("status = ?", future.JobStatusCanceled).
+		Set("error = ?", errorMessage).
+		Set("completed_at = ?", c.Clock.Now().UTC()).
+		Where("id = ?", job.ID).
+		Where("status = ?", future.JobStatusWaiting).
+		Exec(ctx)
+	if err != nil {
+		return 0, utils.WrapError(err, "failed to cancel abandoned future job %d", job.ID)
+	}
+	canceled, err := result.RowsAffected()
+	if err != nil {
+		return 0, utils.WrapError(err, "failed to get canceled rows")
+	}
+	if canceled > 0 {
+		utils.Log(utils.InfoLevel).BT().Send("Canceled waiting job %d: %s", job.ID, errorMessage)
+	}
+	return canceled, nil
+}
+
+func (c *Core) inspectWorkflow(ctx context.Context, workflowID string) (*future.WorkflowStatus, error) {
+	var statuses []future.JobStatus
+	err := c.DB.NewSelect().Model((*FutureJob)(nil)).
+		Column("status").
+		Where("workflow_id = ?", workflowID).
+		Scan(ctx, &statuses)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to inspect future workflow %v", workflowID)
+	}
+	return future.NewWorkflowStatus(workflowID, statuses), nil
+}
+
+func newSpecJob(spec future.JobSpec, output string, workflowID string, now time.Time) *FutureJob {
+	return &FutureJob{
+		ActionIdentifier: uuid.New().String(),
+		ActionType:       spec.ActionType,
+		ActionParams:     spec.ParamsFrom(output),
+		TriggeredAt:      now.Add(spec.TriggerAfter),
+		Status:           future.JobStatusPending,
+		WorkflowID:       workflowID,
+		Successors:       spec.Successors,
+	}
+}
@@ -0,0 +1,171 @@
+package future
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+)
+
+const (
+	DefaultAdminAlertCapacity = 100
+	DefaultWebhookTimeout     = 5 * time.Second
+)
+
+type DeadLetter struct {
+	Job      Job          `json:"job"`
+	Errors   []string     `json:"errors"`
+	Attempts []JobAttempt `json:"attempts"`
+}
+
+func NewDeadLetter(job Job, attempts []JobAttempt) DeadLetter {
+	chain := []string{}
+	for _, attempt := range attempts {
+		if attempt.Error != "" {
+			chain = append(chain, attempt.Error)
+		}
+	}
+	return DeadLetter{
+		Job:      job,
+		Errors:   chain,
+		Attempts: attempts,
+	}
+}
+
+type AlertSink interface {
+	Alert(ctx context.Context, letter DeadLetter) error
+}
+
+func (s *Scheduler) AddAlertSink(sink AlertSink) {
+	s.AlertSinks = append(s.AlertSinks, sink)
+}
+
+func (s *Scheduler) deadLetter(job *Job, tx Transaction, reason FailureReason, cause error) {
+	if err := tx.DeadLetter(s.CancelableCtx, reason, cause.Error()); err != nil {
+		utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to dead-letter job: %s", job.ID)
+		return
+	}
+	s.alert(job)
+	s.releaseWaiting()
+}
+
+func (s *Scheduler) alert(job *Job) {
+	if len(s.AlertSinks) == 0 {
+		return
+	}
+
+	letter := NewDeadLetter(*job, nil)
+	if inspected, err := s.Core.Inspect(s.CancelableCtx, job.ID); err == nil {
+		letter.Job = *inspected
+	}
+	if attempts, err := s.Core.History(s.CancelableCtx, job.ID); err == nil {
+		letter = NewDeadLetter(letter.Job, attempts)
+	} else {
+		utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to get history of dead-lettered job: %s", job.ID)
+	}
+	for _, sink := range s.AlertSinks {
+		if err := sink.Alert(s.CancelableCtx, letter); err != nil {
+			utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to alert dead-lettered job: %s", job.ID)
+		}
+	}
+}
+
+type LogAlertSink struct{}
+
+func NewLogAlertSink() *LogAlertSink {
+	return &LogAlertSink{}
+}
+
+func (s *LogAlertSink) Alert(ctx context.Context, letter DeadLetter) error {
+	utils.Log(utils.ErrorLevel).BT().Send(
+		"Job %s (%s) dead-lettered after %d attempts: %s",
+		letter.Job.ID, letter.Job.ActionType, letter.Job.Attempts, letter.Job.Error,
+	)
+	return nil
+}
+
+type WebhookAlertSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhookAlertSink(url string, timeout time.Duration) *WebhookAlertSink {
+	if timeout <= 0 {
+		timeout = DefaultWebhookTimeout
+	}
+	return &WebhookAlertSink{
+		URL:    url,
+		Client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *WebhookAlertSink) Alert(ctx context.Context, letter DeadLetter) error {
+	body, err := json.Marshal(letter)
+	if err != nil {
+		return utils.WrapError(err, "failed to marshal dead letter")
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return utils.WrapError(err, "failed to create webhook request")
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := s.Client.Do(request)
+	if err != nil {
+		return utils.WrapError(err, "failed to send webhook request")
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return utils.NewError("webhook responded with status %d", response.StatusCode)
+	}
+	return nil
+}
+
+type AdminNotification struct {
+	DeadLetter DeadLetter `json:"dead_letter"`
+	NotifiedAt time.Time  `json:"notified_at"`
+}
+
+// AdminAlertSink keeps the most recent dead letters of this replica in memory for the admin API.
+type AdminAlertSink struct {
+	Capacity      int
+	Notifications []AdminNotification
+	Mutex         sync.Mutex
+}
+
+func NewAdminAlertSink(capacity int) *AdminAlertSink {
+	if capacity <= 0 {
+		capacity = DefaultAdminAlertCapacity
+	}
+	return &AdminAlertSink{
+		Capacity:      capacity,
+		Notifications: make([]AdminNotification, 0, capacity),
+	}
+}
+
+func (s *AdminAlertSink) Alert(ctx context.Context, letter DeadLetter) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	if len(s.Notifications) == s.Capacity {
+		s.Notifications = s.Notifications[1:]
+	}
+	s.Notifications = append(s.Notifications, AdminNotification{
+		DeadLetter: letter,
+		NotifiedAt: time.Now().UTC(),
+	})
+	return nil
+}
+
+func (s *AdminAlertSink) Recent() []AdminNotification {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	recent := make([]AdminNotification, 0, len(s.Notifications))
+	for i := len(s.Notifications) - 1; i >= 0; i-- {
+		recent = append(recent, s.Notifications[i])
+	}
+	return recent
+}
@@ -6,24 +6,69 @@ import (
 
 type JobType string
 type JobStatus string
+type FailureReason string
 
 const (
-	JobStatusPending   JobStatus = "pending"
-	JobStatusRunning   JobStatus = "running"
-	JobStatusCompleted JobStatus = "completed"
-	JobStatusFailed    JobStatus = "failed"
-	JobStatusCanceled  JobStatus = "canceled"
+	JobStatusWaiting      JobStatus = "waiting"
+	JobStatusPending      JobStatus = "pending"
+	JobStatusRunning      JobStatus = "running"
+	JobStatusCompleted    JobStatus = "completed"
+	JobStatusFailed       JobStatus = "failed"
+	JobStatusCanceled     JobStatus = "canceled"
+	JobStatusDeadLettered JobStatus = "dead_lettered"
 )
 
+const (
+	FailureReasonError   FailureReason = "error"
+	FailureReasonPanic   FailureReason = "panic"
+	FailureReasonTimeout FailureReason = "timeout"
+	FailureReasonLease   FailureReason = "lease_expired"
+)
+
+// CanRequeue reports whether an operator may send a finished job back to pending.
+func CanRequeue(status JobStatus) bool {
+	return status == JobStatusDeadLettered || status == JobStatusFailed || status == JobStatusCanceled
+}
+
 type Job struct {
 	ID               string    `json:"id"`
 	ActionIdentifier string    `json:"action_identifier"`
 	ActionType       JobType   `json:"action_type"`
 	ActionParams     string    `json:"action_params"`
 	TriggeredAt      time.Time `json:"triggered_at"`
+	NextAttemptAt    time.Time `json:"next_attempt_at"`
+	Attempts         int       `json:"attempts"`
 	CompletedAt      time.Time `json:"completed_at"`
 	Status           JobStatus `json:"status"`
+	WorkerID         string    `json:"worker_id"`
+	LeaseExpiresAt   time.Time `json:"lease_expires_at"`
 	Error            string    `json:"error"`
+	WorkflowID       string    `json:"workflow_id"`
+	ParentID         string    `json:"parent_id"`
+	DependsOn        []string  `json:"depends_on"`
+	Successors       []JobSpec `json:"successors"`
+	Output           string    `json:"output"`
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
+
+	Workflow *WorkflowStatus `json:"workflow,omitempty"`
+}
+
+type JobAttempt struct {
+	Attempt    int           `json:"attempt"`
+	WorkerID   string        `json:"worker_id"`
+	Outcome    JobStatus     `json:"outcome"`
+	Reason     FailureReason `json:"reason"`
+	Error      string        `json:"error"`
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+}
+
+type JobFilter struct {
+	Status     JobStatus
+	ActionType JobType
+	From       time.Time
+	To         time.Time
+	Offset     int
+	Limit      int
 }
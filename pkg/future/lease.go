@@ -0,0 +1,97 @@
+package future
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+)
+
+const (
+	DefaultLeaseDuration     = 1 * time.Minute
+	DefaultHeartbeatInterval = 20 * time.Second
+	DefaultReapInterval      = 30 * time.Second
+)
+
+var ErrLeaseLost = errors.New("future job lease lost")
+
+func NewWorkerID() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "worker"
+	}
+	return hostname + "-" + uuid.New().String()[:8]
+}
+
+func (s *Scheduler) startHeartbeat(job *Job, tx Transaction, cancel context.CancelCauseFunc) func() {
+	interval := s.Config.Lease.HeartbeatInterval
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := s.Clock.Ticker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := tx.Heartbeat(s.CancelableCtx); err != nil {
+					utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to extend lease for job: %s", job.ID)
+					if errors.Is(err, ErrLeaseLost) {
+						cancel(err)
+						return
+					}
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func (s *Scheduler) startReaper() {
+	interval := s.Config.Lease.ReapInterval
+	if interval <= 0 {
+		interval = DefaultReapInterval
+	}
+	s.WaitGroup.Add(1)
+	go func() {
+		defer s.WaitGroup.Done()
+		ticker := s.Clock.Ticker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.QuitSignal:
+				return
+			case <-ticker.C:
+				s.reapExpired()
+			}
+		}
+	}()
+}
+
+func (s *Scheduler) reapExpired() {
+	reaped, deadLettered, err := s.Core.ReapExpired(s.CancelableCtx, s.RetryPolicyOf)
+	if err != nil {
+		utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to reap expired jobs")
+	}
+	if reaped > int64(len(deadLettered)) {
+		utils.Log(utils.InfoLevel).BT().Send("Returned %d jobs with expired leases to pending", reaped-int64(len(deadLettered)))
+	}
+	for _, job := range deadLettered {
+		utils.Log(utils.WarnLevel).BT().Send("Dead-lettered job %s after its lease expired on attempt %d", job.ID, job.Attempts)
+		s.alert(&job)
+	}
+	if len(deadLettered) > 0 {
+		s.releaseWaiting()
+	}
+}
@@ -0,0 +1,368 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/google/uuid"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+)
+
+var _ future.Core = &Core{}
+
+type Core struct {
+	Clock          clock.Clock
+	WorkerID       string
+	LeaseDuration  time.Duration
+	Jobs           map[int64]*future.Job
+	LastJobID      int64
+	Attempts       map[int64][]future.JobAttempt
+	Schedules      map[string]*future.Schedule
+	LastScheduleID int64
+	WakeSignal     chan struct{}
+	Mutex          sync.Mutex
+}
+
+func NewCore(clk clock.Clock, workerID string, leaseDuration time.Duration) *Core {
+	if leaseDuration <= 0 {
+		leaseDuration = future.DefaultLeaseDuration
+	}
+	return &Core{
+		Clock:         clk,
+		WorkerID:      workerID,
+		LeaseDuration: leaseDuration,
+		Jobs:          make(map[int64]*future.Job),
+		Attempts:      make(map[int64][]future.JobAttempt),
+		Schedules:     make(map[string]*future.Schedule),
+		WakeSignal:    make(chan struct{}, 1),
+	}
+}
+
+func (c *Core) Wakeup() <-chan struct{} {
+	return c.WakeSignal
+}
+
+func (c *Core) Create(ctx context.Context, actionType future.JobType, actionParams string, triggerAfter time.Duration, actionIdentifier ...string) (*future.Job, error) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	return c.create(actionType, actionParams, triggerAfter, actionIdentifier...), nil
+}
+
+func (c *Core) create(actionType future.JobType, actionParams string, triggerAfter time.Duration, actionIdentifier ...string) *future.Job {
+	actionIdentifierValue := uuid.New().String()
+	if len(actionIdentifier) > 0 && actionIdentifier[0] != "" {
+		actionIdentifierValue = actionIdentifier[0]
+	}
+
+	now := c.Clock.Now().UTC()
+	c.LastJobID++
+	job := &future.Job{
+		ID:               strconv.FormatInt(c.LastJobID, 10),
+		ActionIdentifier: actionIdentifierValue,
+		ActionType:       actionType,
+		ActionParams:     actionParams,
+		TriggeredAt:      now.Add(triggerAfter),
+		Status:           future.JobStatusPending,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	c.Jobs[c.LastJobID] = job
+	future.Signal(c.WakeSignal)
+	copied := *job
+	return &copied
+}
+
+func (c *Core) Update(ctx context.Context, ID string, actionType future.JobType, actionParams string, triggerAfter ...time.Duration) error {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	job, ok := c.lookup(ID)
+	if !ok {
+		return nil
+	}
+	job.ActionType = actionType
+	job.ActionParams = actionParams
+	if len(triggerAfter) > 0 {
+		job.TriggeredAt = c.Clock.Now().UTC().Add(triggerAfter[0])
+	}
+	job.UpdatedAt = c.Clock.Now().UTC()
+	future.Signal(c.WakeSignal)
+	return nil
+}
+
+func (c *Core) Cancel(ctx context.Context, ID string) error {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	job, ok := c.lookup(ID)
+	if !ok {
+		return nil
+	}
+	job.Status = future.JobStatusCanceled
+	job.CompletedAt = c.Clock.Now().UTC()
+	job.UpdatedAt = job.CompletedAt
+	return nil
+}
+
+func (c *Core) Reschdule(ctx context.Context, ID string, triggerAfter time.Duration, onlyPending bool) error {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	job, ok := c.lookup(ID)
+	if !ok {
+		return nil
+	}
+	if onlyPending && job.Status != future.JobStatusPending {
+		return nil
+	}
+	job.TriggeredAt = c.Clock.Now().UTC().Add(triggerAfter)
+	if !onlyPending {
+		job.Status = future.JobStatusPending
+		job.NextAttemptAt = time.Time{}
+		job.WorkerID = ""
+		job.LeaseExpiresAt = time.Time{}
+	}
+	job.UpdatedAt = c.Clock.Now().UTC()
+	future.Signal(c.WakeSignal)
+	return nil
+}
+
+// Requeue refuses jobs that are still in flight and fan-in jobs whose dependencies have not all completed.
+func (c *Core) Requeue(ctx context.Context, ID string) (bool, error) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	job, ok := c.lookup(ID)
+	if !ok || !future.CanRequeue(job.Status) {
+		return false, nil
+	}
+	outstanding := slices.ContainsFunc(job.DependsOn, func(ID string) bool {
+		dependency, ok := c.lookup(ID)
+		return ok && dependency.Status != future.JobStatusCompleted
+	})
+	if outstanding {
+		return false, nil
+	}
+	now := c.Clock.Now().UTC()
+	job.Status = future.JobStatusPending
+	job.TriggeredAt = now
+	job.NextAttemptAt = time.Time{}
+	job.CompletedAt = time.Time{}
+	job.WorkerID = ""
+	job.LeaseExpiresAt = time.Time{}
+	job.UpdatedAt = now
+	future.Signal(c.WakeSignal)
+	return true, nil
+}
+
+func (c *Core) Replay(ctx context.Context, ID string) (bool, error) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	job, ok := c.lookup(ID)
+	if !ok || job.Status != future.JobStatusDeadLettered {
+		return false, nil
+	}
+	now := c.Clock.Now().UTC()
+	job.Status = future.JobStatusPending
+	job.TriggeredAt = now
+	job.Attempts = 0
+	job.NextAttemptAt = time.Time{}
+	job.CompletedAt = time.Time{}
+	job.WorkerID = ""
+	job.Error = ""
+	job.UpdatedAt = now
+	future.Signal(c.WakeSignal)
+	return true, nil
+}
+
+func (c *Core) Inspect(ctx context.Context, ID string) (*future.Job, error) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	job, ok := c.lookup(ID)
+	if !ok {
+		return nil, utils.WrapError(sql.ErrNoRows, "failed to inspect future job")
+	}
+	return c.inspect(job), nil
+}
+
+func (c *Core) FindBy(ctx context.Context, actionIdentifier string) (*future.Job, error) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	for _, job := range c.sorted(c.compareID) {
+		if job.ActionIdentifier == actionIdentifier {
+			copied := *job
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *Core) RunNext(ctx context.Context, ignoreTriggerAfter bool, excludeTypes ...future.JobType) (*future.Job, future.Transaction, error) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	now := c.Clock.Now().UTC()
+	candidates := c.sorted(func(a, b *future.Job) int {
+		if order := a.TriggeredAt.Compare(b.TriggeredAt); order != 0 {
+			return order
+		}
+		return c.compareID(a, b)
+	})
+	for _, job := range candidates {
+		if job.Status != future.JobStatusPending || slices.Contains(excludeTypes, job.ActionType) {
+			continue
+		}
+		if !ignoreTriggerAfter && (job.TriggeredAt.After(now) || job.NextAttemptAt.After(now)) {
+			continue
+		}
+
+		job.Status = future.JobStatusRunning
+		job.Attempts++
+		job.WorkerID = c.WorkerID
+		job.LeaseExpiresAt = now.Add(c.LeaseDuration)
+		job.UpdatedAt = now
+
+		copied := *job
+		copied.Successors = slices.Clone(job.Successors)
+		return &copied, &Transaction{Core: c, ID: job.ID, WorkerID: c.WorkerID, StartedAt: now}, nil
+	}
+	return nil, nil, nil
+}
+
+func (c *Core) NextTriggerAt(ctx context.Context) (time.Time, error) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	next := time.Time{}
+	for _, job := range c.Jobs {
+		if job.Status != future.JobStatusPending {
+			continue
+		}
+		triggerAt := job.TriggeredAt
+		if job.NextAttemptAt.After(triggerAt) {
+			triggerAt = job.NextAttemptAt
+		}
+		if next.IsZero() || triggerAt.Before(next) {
+			next = triggerAt
+		}
+	}
+	return next, nil
+}
+
+func (c *Core) DeletePermanently(ctx context.Context, ID string) error {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	id, err := strconv.ParseInt(ID, 10, 64)
+	if err != nil {
+		return nil
+	}
+	delete(c.Jobs, id)
+	delete(c.Attempts, id)
+	return nil
+}
+
+func (c *Core) ReapExpired(ctx context.Context, policyOf func(future.JobType) future.RetryPolicy) (int64, []future.Job, error) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	now := c.Clock.Now().UTC()
+	reaped := int64(0)
+	deadLettered := []future.Job{}
+	for _, job := range c.Jobs {
+		if job.Status != future.JobStatusRunning || !job.LeaseExpiresAt.Before(now) {
+			continue
+		}
+		reaped++
+		if policyOf(job.ActionType).ShouldRetry(job.Attempts) {
+			job.Status = future.JobStatusPending
+			job.WorkerID = ""
+			job.LeaseExpiresAt = time.Time{}
+			job.UpdatedAt = now
+			continue
+		}
+		errorMessage := fmt.Sprintf("lease of %s expired on attempt %d", job.WorkerID, job.Attempts)
+		jobTx := &Transaction{Core: c, ID: job.ID, WorkerID: job.WorkerID, StartedAt: now}
+		jobTx.record(job, future.JobStatusDeadLettered, future.FailureReasonLease, errorMessage, now)
+		job.Status = future.JobStatusDeadLettered
+		job.Error = errorMessage
+		job.CompletedAt = now
+		job.WorkerID = ""
+		job.LeaseExpiresAt = time.Time{}
+		job.UpdatedAt = now
+		deadLettered = append(deadLettered, *job)
+	}
+	if reaped > 0 {
+		future.Signal(c.WakeSignal)
+	}
+	return reaped, deadLettered, nil
+}
+
+func (c *Core) List(ctx context.Context, filter future.JobFilter) ([]future.Job, int, error) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	matched := []future.Job{}
+	jobs := c.sorted(func(a, b *future.Job) int {
+		if order := b.TriggeredAt.Compare(a.TriggeredAt); order != 0 {
+			return order
+		}
+		return c.compareID(b, a)
+	})
+	for _, job := range jobs {
+		if filter.Status != "" && job.Status != filter.Status {
+			continue
+		}
+		if filter.ActionType != "" && job.ActionType != filter.ActionType {
+			continue
+		}
+		if !filter.From.IsZero() && job.TriggeredAt.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && job.TriggeredAt.After(filter.To) {
+			continue
+		}
+		matched = append(matched, *job)
+	}
+
+	total := len(matched)
+	if filter.Limit > 0 {
+		start := min(filter.Offset, total)
+		matched = matched[start:min(start+filter.Limit, total)]
+	}
+	return matched, total, nil
+}
+
+func (c *Core) History(ctx context.Context, ID string) ([]future.JobAttempt, error) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	id, err := strconv.ParseInt(ID, 10, 64)
+	if err != nil {
+		return nil, utils.WrapError(err, "invalid future job id: %v", ID)
+	}
+	return slices.Clone(c.Attempts[id]), nil
+}
+
+func (c *Core) lookup(ID string) (*future.Job, bool) {
+	id, err := strconv.ParseInt(ID, 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	job, ok := c.Jobs[id]
+	return job, ok
+}
+
+func (c *Core) sorted(compare func(a, b *future.Job) int) []*future.Job {
+	jobs := make([]*future.Job, 0, len(c.Jobs))
+	for _, job := range c.Jobs {
+		jobs = append(jobs, job)
+	}
+	slices.SortFunc(jobs, compare)
+	return jobs
+}
+
+func (c *Core) compareID(a, b *future.Job) int {
+	left, _ := strconv.ParseInt(a.ID, 10, 64)
+	right, _ := strconv.ParseInt(b.ID, 10, 64)
+	return int(left - right)
+}
@@ -0,0 +1,754 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+)
+
+var testcases_Core = []struct {
+	name     string
+	scenario func(t *testing.T, tester *tester_Core)
+}{
+	{
+		name: "Success Case - RunNext Orders By TriggeredAt",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			late := tester.create(t, "test", 2*time.Minute)
+			early := tester.create(t, "test", time.Minute)
+			tester.mockedClock.Add(3 * time.Minute)
+
+			tester.expectNext(t, early.ID)
+			tester.expectNext(t, late.ID)
+			tester.expectNext(t, "")
+		},
+	},
+	{
+		name: "Success Case - RunNext Waits For TriggeredAt",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			job := tester.create(t, "test", time.Hour)
+
+			tester.expectNext(t, "")
+			tester.mockedClock.Add(59 * time.Minute)
+			tester.expectNext(t, "")
+			tester.mockedClock.Add(time.Minute)
+			tester.expectNext(t, job.ID)
+		},
+	},
+	{
+		name: "Success Case - RunNext Ignores TriggeredAt",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			job := tester.create(t, "test", time.Hour)
+
+			next, _, err := tester.core.RunNext(context.Background(), true)
+			if err != nil || next == nil || next.ID != job.ID {
+				t.Fatalf("expected job %s, got %v (%v)", job.ID, next, err)
+			}
+		},
+	},
+	{
+		name: "Success Case - RunNext Excludes Types",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			tester.create(t, "excluded", 0)
+			job := tester.create(t, "included", 0)
+
+			next, _, err := tester.core.RunNext(context.Background(), false, "excluded")
+			if err != nil || next == nil || next.ID != job.ID {
+				t.Fatalf("expected job %s, got %v (%v)", job.ID, next, err)
+			}
+		},
+	},
+	{
+		name: "Success Case - Status Transitions",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			ctx := context.Background()
+			job := tester.create(t, "test", 0)
+
+			next, tx, _ := tester.core.RunNext(ctx, false)
+			if next.Status != future.JobStatusRunning || next.Attempts != 1 || next.WorkerID != "tester" {
+				t.Fatalf("expected running job claimed by tester, got %+v", next)
+			}
+			if err := tx.Complete(ctx, "", nil); err != nil {
+				t.Fatalf("failed to complete job: %v", err)
+			}
+			tester.expectStatus(t, job.ID, future.JobStatusCompleted)
+
+			if err := tester.core.Reschdule(ctx, job.ID, time.Minute, true); err != nil {
+				t.Fatalf("failed to reschedule job: %v", err)
+			}
+			tester.expectStatus(t, job.ID, future.JobStatusCompleted)
+
+			if err := tester.core.Reschdule(ctx, job.ID, time.Minute, false); err != nil {
+				t.Fatalf("failed to reschedule job: %v", err)
+			}
+			tester.expectStatus(t, job.ID, future.JobStatusPending)
+
+			if err := tester.core.Cancel(ctx, job.ID); err != nil {
+				t.Fatalf("failed to cancel job: %v", err)
+			}
+			tester.expectStatus(t, job.ID, future.JobStatusCanceled)
+		},
+	},
+	{
+		name: "Success Case - Retry Waits For Backoff",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			ctx := context.Background()
+			job := tester.create(t, "test", 0)
+
+			_, tx, _ := tester.core.RunNext(ctx, false)
+			if err := tx.Retry(ctx, future.FailureReasonError, "failed", time.Minute); err != nil {
+				t.Fatalf("failed to retry job: %v", err)
+			}
+			tester.expectStatus(t, job.ID, future.JobStatusPending)
+			tester.expectNext(t, "")
+
+			tester.mockedClock.Add(time.Minute)
+			next := tester.expectNext(t, job.ID)
+			if next.Attempts != 2 || next.Error != "failed" {
+				t.Fatalf("expected second attempt with previous error, got %+v", next)
+			}
+		},
+	},
+	{
+		name: "Success Case - FindBy Action Identifier",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			ctx := context.Background()
+			job, _ := tester.core.Create(ctx, "test", "{}", time.Minute, "session")
+
+			found, err := tester.core.FindBy(ctx, "session")
+			if err != nil || found == nil || found.ID != job.ID {
+				t.Fatalf("expected job %s, got %v (%v)", job.ID, found, err)
+			}
+			missing, err := tester.core.FindBy(ctx, "unknown")
+			if err != nil || missing != nil {
+				t.Fatalf("expected no job, got %v (%v)", missing, err)
+			}
+		},
+	},
+	{
+		name: "Success Case - List And History",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			ctx := context.Background()
+			first := tester.create(t, "test", 0)
+			tester.create(t, "other", time.Minute)
+			tester.create(t, "test", 2*time.Minute)
+
+			_, tx, _ := tester.core.RunNext(ctx, false)
+			tx.Retry(ctx, future.FailureReasonError, "failed", 0)
+			_, tx, _ = tester.core.RunNext(ctx, false)
+			tx.Complete(ctx, "", nil)
+
+			jobs, total, err := tester.core.List(ctx, future.JobFilter{ActionType: "test", Limit: 1})
+			if err != nil || total != 2 || len(jobs) != 1 || jobs[0].ID == first.ID {
+				t.Fatalf("expected latest of 2 test jobs, got %v of %d (%v)", jobs, total, err)
+			}
+			jobs, total, _ = tester.core.List(ctx, future.JobFilter{Status: future.JobStatusCompleted})
+			if total != 1 || jobs[0].ID != first.ID {
+				t.Fatalf("expected completed job %s, got %v", first.ID, jobs)
+			}
+
+			history, err := tester.core.History(ctx, first.ID)
+			if err != nil || len(history) != 2 {
+				t.Fatalf("expected 2 attempts, got %v (%v)", history, err)
+			}
+			if history[0].Outcome != future.JobStatusPending || history[0].Error != "failed" || history[1].Outcome != future.JobStatusCompleted || history[1].Attempt != 2 {
+				t.Fatalf("unexpected history: %+v", history)
+			}
+		},
+	},
+	{
+		name: "Success Case - Replay Dead Letter",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			ctx := context.Background()
+			job := tester.create(t, "test", 0)
+
+			if replayed, _ := tester.core.Replay(ctx, job.ID); replayed {
+				t.Fatalf("expected pending job not to be replayed")
+			}
+			_, tx, _ := tester.core.RunNext(ctx, false)
+			if err := tx.DeadLetter(ctx, future.FailureReasonError, "exhausted"); err != nil {
+				t.Fatalf("failed to dead-letter job: %v", err)
+			}
+			tester.expectStatus(t, job.ID, future.JobStatusDeadLettered)
+			tester.expectNext(t, "")
+
+			if replayed, err := tester.core.Replay(ctx, job.ID); err != nil || !replayed {
+				t.Fatalf("expected job to be replayed (%v)", err)
+			}
+			next := tester.expectNext(t, job.ID)
+			if next.Attempts != 1 || next.Error != "" {
+				t.Fatalf("expected fresh attempt, got %+v", next)
+			}
+		},
+	},
+	{
+		name: "Success Case - Successors Follow Output",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			ctx := context.Background()
+			job, _ := tester.core.Enqueue(ctx, future.JobSpec{
+				ActionType: "first",
+				Successors: []future.JobSpec{{
+					ActionType: "second",
+					Successors: []future.JobSpec{{ActionType: "third", ActionParams: `{"fixed":true}`}},
+				}},
+			})
+
+			_, tx, _ := tester.core.RunNext(ctx, false)
+			tx.Complete(ctx, `{"summary":1}`, job.Successors)
+			second := tester.expectNext(t, "2")
+			if second.ActionParams != `{"summary":1}` || second.ParentID != job.ID || second.WorkflowID != job.WorkflowID {
+				t.Fatalf("expected successor derived from output, got %+v", second)
+			}
+
+			inspected, _ := tester.core.Inspect(ctx, job.ID)
+			if inspected.Workflow == nil || inspected.Workflow.Status != future.JobStatusRunning || inspected.Workflow.Total != 2 {
+				t.Fatalf("expected running workflow of 2 jobs, got %+v", inspected.Workflow)
+			}
+		},
+	},
+	{
+		name: "Success Case - Fan-In Waits For Dependencies",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			ctx := context.Background()
+			summary, _ := tester.core.Enqueue(ctx, future.JobSpec{ActionType: "summary"})
+			statistics := tester.create(t, "statistics", 0)
+			notify, err := tester.core.Await(ctx, []string{summary.ID, statistics.ID}, future.JobSpec{ActionType: "notify"})
+			if err != nil || notify.Status != future.JobStatusWaiting || notify.WorkflowID != summary.WorkflowID {
+				t.Fatalf("expected waiting job in workflow %s, got %+v (%v)", summary.WorkflowID, notify, err)
+			}
+			if _, err := tester.core.Await(ctx, []string{"99"}, future.JobSpec{ActionType: "notify"}); err == nil {
+				t.Fatalf("expected unknown dependency to be rejected")
+			}
+
+			for range 2 {
+				_, tx, _ := tester.core.RunNext(ctx, false)
+				tester.expectStatus(t, notify.ID, future.JobStatusWaiting)
+				tx.Complete(ctx, "", nil)
+			}
+			if released, _ := tester.core.ReleaseWaiting(ctx); released != 1 {
+				t.Fatalf("expected fan-in job to be released, released %d", released)
+			}
+			tester.expectNext(t, notify.ID)
+		},
+	},
+	{
+		name: "Failure Case - Fan-In Canceled By Dead-Lettered Dependency",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			ctx := context.Background()
+			summary := tester.create(t, "summary", 0)
+			statistics := tester.create(t, "statistics", time.Hour)
+			notify, _ := tester.core.Await(ctx, []string{summary.ID, statistics.ID}, future.JobSpec{ActionType: "notify"})
+			report, _ := tester.core.Await(ctx, []string{notify.ID}, future.JobSpec{ActionType: "report"})
+
+			_, tx, _ := tester.core.RunNext(ctx, false)
+			tx.DeadLetter(ctx, future.FailureReasonError, "summary failed")
+			if released, _ := tester.core.ReleaseWaiting(ctx); released != 0 {
+				t.Fatalf("expected nothing to be released, released %d", released)
+			}
+
+			tester.expectStatus(t, notify.ID, future.JobStatusCanceled)
+			tester.expectStatus(t, report.ID, future.JobStatusCanceled)
+			tester.expectStatus(t, statistics.ID, future.JobStatusPending)
+			if inspected, _ := tester.core.Inspect(ctx, notify.ID); inspected.Error != future.AbandonedError(summary.ID, future.JobStatusDeadLettered) {
+				t.Fatalf("expected abandoned reason on fan-in job, got %q", inspected.Error)
+			}
+
+			tester.mockedClock.Add(time.Hour)
+			tester.expectNext(t, statistics.ID)
+			tester.expectNext(t, "")
+		},
+	},
+	{
+		name: "Failure Case - Requeue Waiting Fan-In Job",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			ctx := context.Background()
+			summary := tester.create(t, "summary", 0)
+			statistics := tester.create(t, "statistics", time.Hour)
+			notify, _ := tester.core.Await(ctx, []string{summary.ID, statistics.ID}, future.JobSpec{ActionType: "notify"})
+
+			if requeued, _ := tester.core.Requeue(ctx, notify.ID); requeued {
+				t.Fatalf("expected waiting job not to be requeued")
+			}
+			tester.expectStatus(t, notify.ID, future.JobStatusWaiting)
+			if requeued, _ := tester.core.Requeue(ctx, statistics.ID); requeued {
+				t.Fatalf("expected pending job not to be requeued")
+			}
+
+			_, tx, _ := tester.core.RunNext(ctx, false)
+			tx.DeadLetter(ctx, future.FailureReasonError, "summary failed")
+			tester.core.ReleaseWaiting(ctx)
+			tester.expectStatus(t, notify.ID, future.JobStatusCanceled)
+			if requeued, _ := tester.core.Requeue(ctx, notify.ID); requeued {
+				t.Fatalf("expected abandoned job not to be requeued while its dependency is dead-lettered")
+			}
+
+			if requeued, err := tester.core.Requeue(ctx, summary.ID); !requeued || err != nil {
+				t.Fatalf("expected dead-lettered job to be requeued, got %v (%v)", requeued, err)
+			}
+			tester.expectNext(t, summary.ID)
+		},
+	},
+	{
+		name: "Failure Case - Complete After Lease Expired",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			ctx := context.Background()
+			job := tester.create(t, "test", 0)
+
+			_, tx, _ := tester.core.RunNext(ctx, false)
+			tester.mockedClock.Add(30 * time.Second)
+			if err := tx.Heartbeat(ctx); err != nil {
+				t.Fatalf("failed to extend lease: %v", err)
+			}
+			tester.mockedClock.Add(45 * time.Second)
+			if reaped, _, _ := tester.core.ReapExpired(ctx, retryTwice); reaped != 0 {
+				t.Fatalf("expected heartbeat to keep the lease, reaped %d", reaped)
+			}
+
+			tester.mockedClock.Add(time.Minute)
+			if reaped, _, _ := tester.core.ReapExpired(ctx, retryTwice); reaped != 1 {
+				t.Fatalf("expected expired lease to be reaped, reaped %d", reaped)
+			}
+			tester.expectStatus(t, job.ID, future.JobStatusPending)
+
+			if err := tx.Complete(ctx, "", nil); !errors.Is(err, future.ErrLeaseLost) {
+				t.Fatalf("expected %v, got %v", future.ErrLeaseLost, err)
+			}
+		},
+	},
+	{
+		name: "Failure Case - Reap Dead-Letters Exhausted Lease",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			ctx := context.Background()
+			job := tester.create(t, "test", 0)
+
+			tester.core.RunNext(ctx, false)
+			tester.mockedClock.Add(2 * time.Minute)
+			if reaped, deadLettered, _ := tester.core.ReapExpired(ctx, retryTwice); reaped != 1 || len(deadLettered) != 0 {
+				t.Fatalf("expected first expired attempt to be retried, reaped %d, dead-lettered %d", reaped, len(deadLettered))
+			}
+
+			tester.core.RunNext(ctx, false)
+			tester.mockedClock.Add(2 * time.Minute)
+			reaped, deadLettered, _ := tester.core.ReapExpired(ctx, retryTwice)
+			if reaped != 1 || len(deadLettered) != 1 || deadLettered[0].ID != job.ID {
+				t.Fatalf("expected exhausted job to be dead-lettered, reaped %d, got %+v", reaped, deadLettered)
+			}
+			tester.expectStatus(t, job.ID, future.JobStatusDeadLettered)
+
+			attempts, _ := tester.core.History(ctx, job.ID)
+			if len(attempts) != 1 || attempts[0].Reason != future.FailureReasonLease {
+				t.Fatalf("expected lease expiry in history, got %+v", attempts)
+			}
+		},
+	},
+	{
+		name: "Success Case - NextRun Computes Cron And Interval",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			now := tester.mockedClock.Now().UTC()
+
+			next, err := future.NextRun(future.ScheduleKindInterval, "90m", now)
+			if err != nil || !next.Equal(now.Add(90*time.Minute)) {
+				t.Fatalf("expected interval run at %v, got %v (%v)", now.Add(90*time.Minute), next, err)
+			}
+			next, err = future.NextRun(future.ScheduleKindCron, "30 9 * * *", now)
+			if expected := time.Date(2025, 1, 1, 9, 30, 0, 0, time.UTC); err != nil || !next.Equal(expected) {
+				t.Fatalf("expected cron run at %v, got %v (%v)", expected, next, err)
+			}
+			next, err = future.NextRun(future.ScheduleKindCron, "0 0 * * *", now)
+			if expected := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC); err != nil || !next.Equal(expected) {
+				t.Fatalf("expected cron run strictly after now at %v, got %v (%v)", expected, next, err)
+			}
+
+			if _, err := future.NextRun(future.ScheduleKindInterval, "0s", now); err == nil {
+				t.Fatalf("expected non-positive interval to be rejected")
+			}
+			if _, err := future.NextRun(future.ScheduleKindCron, "not a cron", now); err == nil {
+				t.Fatalf("expected invalid cron expression to be rejected")
+			}
+			if _, err := future.NextRun("unknown", "1m", now); err == nil {
+				t.Fatalf("expected unknown schedule kind to be rejected")
+			}
+		},
+	},
+	{
+		name: "Success Case - Materialize Due Schedule",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			ctx := context.Background()
+			registered := tester.register(t, "every-10m", future.ScheduleKindInterval, "10m")
+
+			if created, _ := tester.core.MaterializeSchedules(ctx, time.Hour); created != 0 {
+				t.Fatalf("expected schedule to wait for its first run, created %d", created)
+			}
+
+			tester.mockedClock.Add(10 * time.Minute)
+			if created, _ := tester.core.MaterializeSchedules(ctx, time.Hour); created != 1 {
+				t.Fatalf("expected one scheduled job, created %d", created)
+			}
+			if created, _ := tester.core.MaterializeSchedules(ctx, time.Hour); created != 0 {
+				t.Fatalf("expected run to materialize once, created %d", created)
+			}
+
+			job, err := tester.core.FindBy(ctx, future.ScheduledIdentifier("every-10m", registered.NextRunAt))
+			if err != nil || job.ActionType != "test" {
+				t.Fatalf("expected scheduled job for due run, got %v (%v)", job, err)
+			}
+			schedule := tester.schedule(t, "every-10m")
+			if !schedule.NextRunAt.Equal(tester.mockedClock.Now().UTC().Add(10*time.Minute)) || !schedule.LastRunAt.Equal(tester.mockedClock.Now().UTC()) {
+				t.Fatalf("expected schedule to advance from now, got %+v", schedule)
+			}
+		},
+	},
+	{
+		name: "Success Case - Materialize Collapses Missed Runs Within Grace",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			ctx := context.Background()
+			tester.register(t, "every-10m", future.ScheduleKindInterval, "10m")
+
+			tester.mockedClock.Add(35 * time.Minute)
+			if created, _ := tester.core.MaterializeSchedules(ctx, time.Hour); created != 1 {
+				t.Fatalf("expected missed runs to collapse into one job, created %d", created)
+			}
+			if schedule := tester.schedule(t, "every-10m"); !schedule.NextRunAt.Equal(tester.mockedClock.Now().UTC().Add(10 * time.Minute)) {
+				t.Fatalf("expected next run computed from now, got %v", schedule.NextRunAt)
+			}
+		},
+	},
+	{
+		name: "Failure Case - Materialize Skips Run Past Misfire Grace",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			ctx := context.Background()
+			tester.register(t, "every-10m", future.ScheduleKindInterval, "10m")
+
+			tester.mockedClock.Add(2 * time.Hour)
+			if created, _ := tester.core.MaterializeSchedules(ctx, 30*time.Minute); created != 0 {
+				t.Fatalf("expected misfired run to be skipped, created %d", created)
+			}
+			if schedule := tester.schedule(t, "every-10m"); !schedule.NextRunAt.After(tester.mockedClock.Now()) {
+				t.Fatalf("expected skipped schedule to advance, got %v", schedule.NextRunAt)
+			}
+
+			tester.mockedClock.Add(10 * time.Minute)
+			if created, _ := tester.core.MaterializeSchedules(ctx, 30*time.Minute); created != 1 {
+				t.Fatalf("expected schedule to resume after the misfire, created %d", created)
+			}
+		},
+	},
+	{
+		name: "Failure Case - Disabled Schedule Does Not Materialize",
+		scenario: func(t *testing.T, tester *tester_Core) {
+			ctx := context.Background()
+			tester.register(t, "every-10m", future.ScheduleKindInterval, "10m")
+			if err := tester.core.EnableSchedule(ctx, "every-10m", false); err != nil {
+				t.Fatalf("failed to disable schedule: %v", err)
+			}
+
+			tester.mockedClock.Add(time.Hour)
+			if created, _ := tester.core.MaterializeSchedules(ctx, time.Hour); created != 0 {
+				t.Fatalf("expected disabled schedule to be skipped, created %d", created)
+			}
+
+			if err := tester.core.EnableSchedule(ctx, "every-10m", true); err != nil {
+				t.Fatalf("failed to enable schedule: %v", err)
+			}
+			if created, _ := tester.core.MaterializeSchedules(ctx, time.Hour); created != 0 {
+				t.Fatalf("expected enabled schedule to resume from now, created %d", created)
+			}
+		},
+	},
+}
+
+func retryTwice(future.JobType) future.RetryPolicy {
+	return future.RetryPolicy{MaxAttempts: 2}
+}
+
+func Test_Core(t *testing.T) {
+	for _, testcase := range testcases_Core {
+		t.Run(testcase.name, func(t *testing.T) {
+			testcase.scenario(t, prepareCoreForTest())
+		})
+	}
+}
+
+func Test_Scheduler(t *testing.T) {
+	tester := prepareCoreForTest()
+	handler := &recordingHandler{handled: make(chan string, 1)}
+
+	scheduler := future.NewScheduler(future.Config{ScheduleCycle: time.Minute}, tester.core, tester.mockedClock)
+	scheduler.AddHandler("test", handler)
+	job := tester.create(t, "test", time.Hour)
+
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+
+	select {
+	case id := <-handler.handled:
+		t.Fatalf("expected job to wait for its trigger, but %s fired", id)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	tester.mockedClock.Add(time.Hour)
+	select {
+	case id := <-handler.handled:
+		if id != job.ID {
+			t.Fatalf("expected job %s, got %s", job.ID, id)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected job %s to fire", job.ID)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		inspected, _ := tester.core.Inspect(context.Background(), job.ID)
+		if inspected.Status == future.JobStatusCompleted {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected job to complete, got %s", inspected.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func Test_Scheduler_Wakeup(t *testing.T) {
+	tester := prepareCoreForTest()
+	handler := &recordingHandler{handled: make(chan string, 1)}
+
+	scheduler := future.NewScheduler(future.Config{ScheduleCycle: time.Minute}, tester.core, tester.mockedClock)
+	scheduler.AddHandler("test", handler)
+
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+
+	time.Sleep(50 * time.Millisecond)
+	job := tester.create(t, "test", 0)
+	select {
+	case id := <-handler.handled:
+		if id != job.ID {
+			t.Fatalf("expected job %s, got %s", job.ID, id)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected job %s to fire without waiting for the next cycle", job.ID)
+	}
+}
+
+func Test_Scheduler_DeadLetter(t *testing.T) {
+	tester := prepareCoreForTest()
+	admin := future.NewAdminAlertSink(1)
+
+	scheduler := future.NewScheduler(future.Config{ScheduleCycle: time.Minute}, tester.core, tester.mockedClock)
+	scheduler.AddHandler("test", &failingHandler{})
+	scheduler.AddAlertSink(admin)
+	job := tester.create(t, "test", 0)
+
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+
+	deadline := time.Now().Add(time.Second)
+	for len(admin.Recent()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected job %s to be dead-lettered", job.ID)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	letter := admin.Recent()[0].DeadLetter
+	if letter.Job.ID != job.ID || letter.Job.Status != future.JobStatusDeadLettered || len(letter.Errors) != 1 {
+		t.Fatalf("unexpected dead letter: %+v", letter)
+	}
+}
+
+func Test_Scheduler_Timeout(t *testing.T) {
+	tester := prepareCoreForTest()
+	admin := future.NewAdminAlertSink(1)
+
+	scheduler := future.NewScheduler(future.Config{ScheduleCycle: time.Minute}, tester.core, tester.mockedClock)
+	scheduler.AddHandler("test", &blockingHandler{started: make(chan string, 1), timeout: 20 * time.Millisecond})
+	scheduler.AddAlertSink(admin)
+	job := tester.create(t, "test", 0)
+
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+
+	deadline := time.Now().Add(time.Second)
+	for len(admin.Recent()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected job %s to time out", job.ID)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	history, _ := tester.core.History(context.Background(), job.ID)
+	if len(history) != 1 || history[0].Reason != future.FailureReasonTimeout {
+		t.Fatalf("expected a timed out attempt, got %+v", history)
+	}
+}
+
+func Test_Scheduler_CancelRunning(t *testing.T) {
+	tester := prepareCoreForTest()
+	handler := &blockingHandler{started: make(chan string, 1)}
+
+	scheduler := future.NewScheduler(future.Config{ScheduleCycle: time.Minute}, tester.core, tester.mockedClock)
+	scheduler.AddHandler("test", handler)
+	job := tester.create(t, "test", 0)
+
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+
+	select {
+	case <-handler.started:
+	case <-time.After(time.Second):
+		t.Fatalf("expected job %s to start", job.ID)
+	}
+	tester.core.Cancel(context.Background(), job.ID)
+	if !scheduler.CancelRunning(job.ID) {
+		t.Fatalf("expected job %s to be running", job.ID)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for scheduler.CancelRunning(job.ID) {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected job %s to stop running", job.ID)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tester.expectStatus(t, job.ID, future.JobStatusCanceled)
+	if history, _ := tester.core.History(context.Background(), job.ID); len(history) != 0 {
+		t.Fatalf("expected canceled job not to record an attempt, got %+v", history)
+	}
+}
+
+func Test_Scheduler_Schedules(t *testing.T) {
+	tester := prepareCoreForTest()
+	tester.register(t, "removed", future.ScheduleKindInterval, "10m")
+
+	scheduler := future.NewScheduler(future.Config{
+		ScheduleCycle: time.Minute,
+		Schedule: future.ScheduleConfig{
+			Definitions: map[string]future.ScheduleDefinition{
+				"kept": {ActionType: "test", Kind: string(future.ScheduleKindCron), Expression: "0 * * * *"},
+			},
+		},
+	}, tester.core, tester.mockedClock)
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+
+	if removed := tester.schedule(t, "removed"); removed.Enabled {
+		t.Fatalf("expected schedule missing from config to be disabled")
+	}
+	if kept := tester.schedule(t, "kept"); !kept.Enabled || kept.ActionParams != "{}" {
+		t.Fatalf("expected configured schedule to be registered, got %+v", kept)
+	}
+}
+
+type tester_Core struct {
+	mockedClock *clock.Mock
+	core        *Core
+}
+
+func (tester *tester_Core) create(t *testing.T, actionType future.JobType, triggerAfter time.Duration) *future.Job {
+	t.Helper()
+	job, err := tester.core.Create(context.Background(), actionType, "{}", triggerAfter)
+	if err != nil {
+		t.Fatalf("failed to create job: %v", err)
+	}
+	return job
+}
+
+func (tester *tester_Core) register(t *testing.T, name string, kind future.ScheduleKind, expression string) *future.Schedule {
+	t.Helper()
+	schedule, err := tester.core.RegisterSchedule(context.Background(), name, "test", "{}", kind, expression)
+	if err != nil {
+		t.Fatalf("failed to register schedule: %v", err)
+	}
+	return schedule
+}
+
+func (tester *tester_Core) schedule(t *testing.T, name string) future.Schedule {
+	t.Helper()
+	schedules, err := tester.core.ListSchedules(context.Background())
+	if err != nil {
+		t.Fatalf("failed to list schedules: %v", err)
+	}
+	for _, schedule := range schedules {
+		if schedule.Name == name {
+			return schedule
+		}
+	}
+	t.Fatalf("expected schedule %s to exist", name)
+	return future.Schedule{}
+}
+
+func (tester *tester_Core) expectNext(t *testing.T, ID string) *future.Job {
+	t.Helper()
+	job, _, err := tester.core.RunNext(context.Background(), false)
+	if err != nil {
+		t.Fatalf("failed to run next job: %v", err)
+	}
+	if ID == "" {
+		if job != nil {
+			t.Fatalf("expected no job, got %s", job.ID)
+		}
+		return nil
+	}
+	if job == nil || job.ID != ID {
+		t.Fatalf("expected job %s, got %v", ID, job)
+	}
+	return job
+}
+
+func (tester *tester_Core) expectStatus(t *testing.T, ID string, status future.JobStatus) {
+	t.Helper()
+	job, err := tester.core.Inspect(context.Background(), ID)
+	if err != nil {
+		t.Fatalf("failed to inspect job: %v", err)
+	}
+	if job.Status != status {
+		t.Fatalf("expected status %s, got %s", status, job.Status)
+	}
+}
+
+type recordingHandler struct {
+	once    sync.Once
+	handled chan string
+}
+
+func (h *recordingHandler) Handle(ctx context.Context, job *future.Job) error {
+	h.once.Do(func() {
+		h.handled <- job.ID
+	})
+	return nil
+}
+
+type failingHandler struct{}
+
+func (h *failingHandler) Handle(ctx context.Context, job *future.Job) error {
+	return errors.New("handler failed")
+}
+
+type blockingHandler struct {
+	started chan string
+	timeout time.Duration
+}
+
+func (h *blockingHandler) Timeout() time.Duration {
+	return h.timeout
+}
+
+func (h *blockingHandler) Handle(ctx context.Context, job *future.Job) error {
+	h.started <- job.ID
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func prepareCoreForTest() *tester_Core {
+	mockedClock := clock.NewMock()
+	mockedClock.Set(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	return &tester_Core{
+		mockedClock: mockedClock,
+		core:        NewCore(mockedClock, "tester", time.Minute),
+	}
+}
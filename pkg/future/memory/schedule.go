@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+)
+
+var _ future.ScheduleCore = &Core{}
+
+func (c *Core) RegisterSchedule(ctx context.Context, name string, actionType future.JobType, actionParams string, kind future.ScheduleKind, expression string) (*future.Schedule, error) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	now := c.Clock.Now().UTC()
+	nextRunAt, err := future.NextRun(kind, expression, now)
+	if err != nil {
+		return nil, utils.WrapError(err, "failed to compute next run for schedule %v", name)
+	}
+
+	schedule, ok := c.Schedules[name]
+	if !ok {
+		c.LastScheduleID++
+		schedule = &future.Schedule{
+			ID:        strconv.FormatInt(c.LastScheduleID, 10),
+			Name:      name,
+			Enabled:   true,
+			NextRunAt: nextRunAt,
+			CreatedAt: now,
+		}
+		c.Schedules[name] = schedule
+	} else if schedule.Kind != kind || schedule.Expression != expression {
+		schedule.NextRunAt = nextRunAt
+	}
+	schedule.ActionType = actionType
+	schedule.ActionParams = actionParams
+	schedule.Kind = kind
+	schedule.Expression = expression
+	schedule.UpdatedAt = now
+	future.Signal(c.WakeSignal)
+
+	copied := *schedule
+	return &copied, nil
+}
+
+func (c *Core) EnableSchedule(ctx context.Context, name string, enabled bool) error {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	schedule, ok := c.Schedules[name]
+	if !ok {
+		return utils.WrapError(sql.ErrNoRows, "failed to get future schedule %v", name)
+	}
+	now := c.Clock.Now().UTC()
+	if enabled && !schedule.Enabled {
+		nextRunAt, err := future.NextRun(schedule.Kind, schedule.Expression, now)
+		if err != nil {
+			return utils.WrapError(err, "failed to compute next run for schedule %v", name)
+		}
+		schedule.NextRunAt = nextRunAt
+	}
+	schedule.Enabled = enabled
+	schedule.UpdatedAt = now
+	future.Signal(c.WakeSignal)
+	return nil
+}
+
+func (c *Core) ListSchedules(ctx context.Context) ([]future.Schedule, error) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	schedules := make([]future.Schedule, 0, len(c.Schedules))
+	for _, schedule := range c.Schedules {
+		schedules = append(schedules, *schedule)
+	}
+	slices.SortFunc(schedules, func(a, b future.Schedule) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return schedules, nil
+}
+
+func (c *Core) DeleteSchedule(ctx context.Context, name string) error {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	delete(c.Schedules, name)
+	return nil
+}
+
+func (c *Core) MaterializeSchedules(ctx context.Context, misfireGrace time.Duration) (int, error) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+
+	now := c.Clock.Now().UTC()
+	created := 0
+	for _, schedule := range c.Schedules {
+		if !schedule.Enabled || schedule.NextRunAt.After(now) {
+			continue
+		}
+		nextRunAt, err := future.NextRun(schedule.Kind, schedule.Expression, now)
+		if err != nil {
+			utils.Log(utils.WarnLevel).Err(err).BT().Send("Skipping schedule %v with invalid expression", schedule.Name)
+			continue
+		}
+		dueAt := schedule.NextRunAt
+		schedule.NextRunAt = nextRunAt
+		schedule.LastRunAt = now
+
+		if misfireGrace > 0 && now.Sub(dueAt) > misfireGrace {
+			utils.Log(utils.InfoLevel).BT().Send("Skipping run of schedule %v missed at %v", schedule.Name, dueAt)
+			continue
+		}
+		c.create(schedule.ActionType, schedule.ActionParams, 0, future.ScheduledIdentifier(schedule.Name, dueAt))
+		created++
+	}
+	return created, nil
+}
+
+func (c *Core) NextScheduledAt(ctx context.Context) (time.Time, error) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	next := time.Time{}
+	for _, schedule := range c.Schedules {
+		if schedule.Enabled && (next.IsZero() || schedule.NextRunAt.Before(next)) {
+			next = schedule.NextRunAt
+		}
+	}
+	return next, nil
+}
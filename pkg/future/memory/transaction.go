@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+)
+
+type Transaction struct {
+	Core      *Core
+	ID        string
+	WorkerID  string
+	StartedAt time.Time
+}
+
+func (t *Transaction) Complete(ctx context.Context, output string, successors []future.JobSpec) error {
+	return t.update(func(job *future.Job, now time.Time) {
+		t.record(job, future.JobStatusCompleted, "", "", now)
+		job.Status = future.JobStatusCompleted
+		job.CompletedAt = now
+		job.LeaseExpiresAt = time.Time{}
+		job.Output = output
+		if len(successors) == 0 {
+			return
+		}
+		if job.WorkflowID == "" {
+			job.WorkflowID = uuid.New().String()
+		}
+		for _, spec := range successors {
+			t.Core.createSpec(spec, output, job.ID, job.WorkflowID, now)
+		}
+	})
+}
+
+func (t *Transaction) Fail(ctx context.Context, errorMessage string) error {
+	return t.update(func(job *future.Job, now time.Time) {
+		t.record(job, future.JobStatusFailed, future.FailureReasonError, errorMessage, now)
+		job.Status = future.JobStatusFailed
+		job.Error = errorMessage
+		job.CompletedAt = now
+		job.LeaseExpiresAt = time.Time{}
+	})
+}
+
+func (t *Transaction) Retry(ctx context.Context, reason future.FailureReason, errorMessage string, retryAfter time.Duration) error {
+	return t.update(func(job *future.Job, now time.Time) {
+		t.record(job, future.JobStatusPending, reason, errorMessage, now)
+		job.Status = future.JobStatusPending
+		job.Error = errorMessage
+		job.NextAttemptAt = now.Add(retryAfter)
+		job.WorkerID = ""
+		job.LeaseExpiresAt = time.Time{}
+	})
+}
+
+func (t *Transaction) DeadLetter(ctx context.Context, reason future.FailureReason, errorMessage string) error {
+	return t.update(func(job *future.Job, now time.Time) {
+		t.record(job, future.JobStatusDeadLettered, reason, errorMessage, now)
+		job.Status = future.JobStatusDeadLettered
+		job.Error = errorMessage
+		job.CompletedAt = now
+		job.LeaseExpiresAt = time.Time{}
+	})
+}
+
+func (t *Transaction) Heartbeat(ctx context.Context) error {
+	return t.update(func(job *future.Job, now time.Time) {
+		job.LeaseExpiresAt = now.Add(t.Core.LeaseDuration)
+	})
+}
+
+func (t *Transaction) update(apply func(job *future.Job, now time.Time)) error {
+	t.Core.Mutex.Lock()
+	defer t.Core.Mutex.Unlock()
+	job, ok := t.Core.lookup(t.ID)
+	if !ok || job.Status != future.JobStatusRunning || job.WorkerID != t.WorkerID {
+		return utils.WrapError(future.ErrLeaseLost, "future job %s is no longer owned by %s", t.ID, t.WorkerID)
+	}
+	now := t.Core.Clock.Now().UTC()
+	apply(job, now)
+	job.UpdatedAt = now
+	return nil
+}
+
+func (t *Transaction) record(job *future.Job, outcome future.JobStatus, reason future.FailureReason, errorMessage string, now time.Time) {
+	id, _ := strconv.ParseInt(job.ID, 10, 64)
+	t.Core.Attempts[id] = append(t.Core.Attempts[id], future.JobAttempt{
+		Attempt:    job.Attempts,
+		WorkerID:   job.WorkerID,
+		Outcome:    outcome,
+		Reason:     reason,
+		Error:      errorMessage,
+		StartedAt:  t.StartedAt,
+		FinishedAt: now,
+	})
+}
@@ -0,0 +1,142 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+)
+
+func (c *Core) Enqueue(ctx context.Context, spec future.JobSpec) (*future.Job, error) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	job := c.createSpec(spec, "", "", uuid.New().String(), c.Clock.Now().UTC())
+	future.Signal(c.WakeSignal)
+	copied := *job
+	return &copied, nil
+}
+
+func (c *Core) Await(ctx context.Context, dependsOn []string, spec future.JobSpec) (*future.Job, error) {
+	if len(dependsOn) == 0 {
+		return nil, utils.NewError("fan-in job requires at least one dependency")
+	}
+	dependsOn = slices.Compact(slices.Sorted(slices.Values(dependsOn)))
+
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	workflowID := ""
+	dependencies := make([]*future.Job, 0, len(dependsOn))
+	for _, ID := range dependsOn {
+		dependency, ok := c.lookup(ID)
+		if !ok {
+			return nil, utils.NewError("some of future job dependencies do not exist: %v", dependsOn)
+		}
+		if workflowID == "" {
+			workflowID = dependency.WorkflowID
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	if workflowID == "" {
+		workflowID = uuid.New().String()
+	}
+	for _, dependency := range dependencies {
+		if dependency.WorkflowID == "" {
+			dependency.WorkflowID = workflowID
+		}
+	}
+
+	job := c.createSpec(spec, "", "", workflowID, c.Clock.Now().UTC())
+	job.Status = future.JobStatusWaiting
+	job.DependsOn = dependsOn
+	c.releaseWaiting()
+	return c.inspect(job), nil
+}
+
+func (c *Core) ReleaseWaiting(ctx context.Context) (int64, error) {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	return c.releaseWaiting(), nil
+}
+
+func (c *Core) releaseWaiting() int64 {
+	released := int64(0)
+	for abandoned := true; abandoned; {
+		// Canceling a waiter can abandon the waiters depending on it in turn.
+		abandoned = false
+		for _, job := range c.Jobs {
+			if job.Status != future.JobStatusWaiting {
+				continue
+			}
+			blocked := false
+			for _, ID := range job.DependsOn {
+				// Dependencies deleted after completion no longer block anything.
+				dependency, ok := c.lookup(ID)
+				if !ok || dependency.Status == future.JobStatusCompleted {
+					continue
+				}
+				blocked = true
+				if future.BlocksDependents(dependency.Status) {
+					c.abandonWaiting(job, ID, dependency.Status)
+					abandoned = true
+					break
+				}
+			}
+			if !blocked {
+				job.Status = future.JobStatusPending
+				job.UpdatedAt = c.Clock.Now().UTC()
+				released++
+			}
+		}
+	}
+	if released > 0 {
+		future.Signal(c.WakeSignal)
+	}
+	return released
+}
+
+func (c *Core) abandonWaiting(job *future.Job, dependencyID string, status future.JobStatus) {
+	now := c.Clock.Now().UTC()
+	job.Status = future.JobStatusCanceled
+	job.Error = future.AbandonedError(dependencyID, status)
+	job.CompletedAt = now
+	job.UpdatedAt = now
+	utils.Log(utils.InfoLevel).BT().Send("Canceled waiting job %s: %s", job.ID, job.Error)
+}
+
+func (c *Core) inspect(job *future.Job) *future.Job {
+	copied := *job
+	if job.WorkflowID == "" {
+		return &copied
+	}
+	statuses := []future.JobStatus{}
+	for _, other := range c.Jobs {
+		if other.WorkflowID == job.WorkflowID {
+			statuses = append(statuses, other.Status)
+		}
+	}
+	copied.Workflow = future.NewWorkflowStatus(job.WorkflowID, statuses)
+	return &copied
+}
+
+func (c *Core) createSpec(spec future.JobSpec, output string, parentID string, workflowID string, now time.Time) *future.Job {
+	c.LastJobID++
+	job := &future.Job{
+		ID:               strconv.FormatInt(c.LastJobID, 10),
+		ActionIdentifier: uuid.New().String(),
+		ActionType:       spec.ActionType,
+		ActionParams:     spec.ParamsFrom(output),
+		TriggeredAt:      now.Add(spec.TriggerAfter),
+		Status:           future.JobStatusPending,
+		WorkflowID:       workflowID,
+		ParentID:         parentID,
+		Successors:       spec.Successors,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	c.Jobs[c.LastJobID] = job
+	return job
+}
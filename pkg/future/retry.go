@@ -0,0 +1,84 @@
+package future
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	RetryPolicyWildcardType = "*"
+)
+
+type RetryPolicy struct {
+	MaxAttempts    int           `mapstructure:"max_attempts" default:"1"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff" default:"30s"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff" default:"30m"`
+	Multiplier     float64       `mapstructure:"multiplier" default:"2"`
+	Jitter         float64       `mapstructure:"jitter" default:"0.2"`
+}
+
+// RetryClassifier can be implemented by a Handler to decide which of its errors are worth retrying.
+type RetryClassifier interface {
+	Retryable(err error) bool
+}
+
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+func IsRetryable(handler Handler, err error) bool {
+	var permanent *PermanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+	if classifier, ok := handler.(RetryClassifier); ok {
+		return classifier.Retryable(err)
+	}
+	return true
+}
+
+func (p RetryPolicy) ShouldRetry(attempts int) bool {
+	return attempts < p.MaxAttempts
+}
+
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(max(attempts-1, 0)))
+	if p.MaxBackoff > 0 {
+		backoff = math.Min(backoff, float64(p.MaxBackoff))
+	}
+	if p.Jitter > 0 {
+		backoff += backoff * p.Jitter * (rand.Float64()*2 - 1)
+	}
+	return max(time.Duration(backoff), 0)
+}
+
+func (s *Scheduler) RetryPolicyOf(actionType JobType) RetryPolicy {
+	if policy, ok := s.Config.Retry[string(actionType)]; ok {
+		return policy
+	}
+	if policy, ok := s.Config.Retry[RetryPolicyWildcardType]; ok {
+		return policy
+	}
+	return RetryPolicy{MaxAttempts: 1}
+}
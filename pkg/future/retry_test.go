@@ -0,0 +1,142 @@
+package future
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var testcases_RetryPolicy_Backoff = []struct {
+	name     string
+	policy   RetryPolicy
+	attempts int
+	minimum  time.Duration
+	maximum  time.Duration
+}{
+	{
+		name:     "Success Case - First Attempt",
+		policy:   RetryPolicy{InitialBackoff: 30 * time.Second, MaxBackoff: time.Hour, Multiplier: 2},
+		attempts: 1,
+		minimum:  30 * time.Second,
+		maximum:  30 * time.Second,
+	},
+	{
+		name:     "Success Case - Exponential Growth",
+		policy:   RetryPolicy{InitialBackoff: 30 * time.Second, MaxBackoff: time.Hour, Multiplier: 2},
+		attempts: 4,
+		minimum:  4 * time.Minute,
+		maximum:  4 * time.Minute,
+	},
+	{
+		name:     "Success Case - Capped By Max Backoff",
+		policy:   RetryPolicy{InitialBackoff: 30 * time.Second, MaxBackoff: 5 * time.Minute, Multiplier: 2},
+		attempts: 20,
+		minimum:  5 * time.Minute,
+		maximum:  5 * time.Minute,
+	},
+	{
+		name:     "Success Case - Multiplier Below One",
+		policy:   RetryPolicy{InitialBackoff: 30 * time.Second, Multiplier: 0.5},
+		attempts: 5,
+		minimum:  30 * time.Second,
+		maximum:  30 * time.Second,
+	},
+	{
+		name:     "Success Case - Jitter Within Bounds",
+		policy:   RetryPolicy{InitialBackoff: 10 * time.Second, MaxBackoff: 10 * time.Second, Multiplier: 2, Jitter: 0.2},
+		attempts: 3,
+		minimum:  8 * time.Second,
+		maximum:  12 * time.Second,
+	},
+}
+
+func Test_RetryPolicy_Backoff(t *testing.T) {
+	for _, testcase := range testcases_RetryPolicy_Backoff {
+		t.Run(testcase.name, func(t *testing.T) {
+			for range 100 {
+				backoff := testcase.policy.Backoff(testcase.attempts)
+				if backoff < testcase.minimum || backoff > testcase.maximum {
+					t.Fatalf("expected backoff within [%v, %v], got %v", testcase.minimum, testcase.maximum, backoff)
+				}
+			}
+		})
+	}
+}
+
+func Test_RetryPolicy_ShouldRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3}
+	for attempts, expected := range []bool{true, true, true, false, false} {
+		if retry := policy.ShouldRetry(attempts); retry != expected {
+			t.Fatalf("expected retry %v after %d attempts, got %v", expected, attempts, retry)
+		}
+	}
+}
+
+type tester_RetryClassifier struct{}
+
+func (tester_RetryClassifier) Handle(context.Context, *Job) error {
+	return nil
+}
+
+func (tester_RetryClassifier) Retryable(err error) bool {
+	return !errors.Is(err, context.Canceled)
+}
+
+var testcases_IsRetryable = []struct {
+	name     string
+	handler  Handler
+	err      error
+	expected bool
+}{
+	{
+		name:     "Success Case - Plain Error",
+		err:      errors.New("failed"),
+		expected: true,
+	},
+	{
+		name:     "Failure Case - Permanent Error",
+		err:      Permanent(errors.New("failed")),
+		expected: false,
+	},
+	{
+		name:     "Success Case - Classifier Retryable",
+		handler:  tester_RetryClassifier{},
+		err:      errors.New("failed"),
+		expected: true,
+	},
+	{
+		name:     "Failure Case - Classifier Not Retryable",
+		handler:  tester_RetryClassifier{},
+		err:      context.Canceled,
+		expected: false,
+	},
+}
+
+func Test_IsRetryable(t *testing.T) {
+	for _, testcase := range testcases_IsRetryable {
+		t.Run(testcase.name, func(t *testing.T) {
+			if retryable := IsRetryable(testcase.handler, testcase.err); retryable != testcase.expected {
+				t.Fatalf("expected retryable %v, got %v", testcase.expected, retryable)
+			}
+		})
+	}
+}
+
+func Test_Scheduler_RetryPolicyOf(t *testing.T) {
+	scheduler := NewScheduler(Config{Retry: map[string]RetryPolicy{
+		"chat":                  {MaxAttempts: 5},
+		RetryPolicyWildcardType: {MaxAttempts: 2},
+	}}, nil, nil)
+	if policy := scheduler.RetryPolicyOf("chat"); policy.MaxAttempts != 5 {
+		t.Fatalf("expected type policy, got %+v", policy)
+	}
+	if policy := scheduler.RetryPolicyOf("other"); policy.MaxAttempts != 2 {
+		t.Fatalf("expected wildcard policy, got %+v", policy)
+	}
+
+	scheduler = NewScheduler(Config{}, nil, nil)
+	if policy := scheduler.RetryPolicyOf("other"); policy.MaxAttempts != 1 {
+		t.Fatalf("expected single attempt without policies, got %+v", policy)
+	}
+}
@@ -0,0 +1,120 @@
+package future
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+)
+
+type ScheduleKind string
+
+const (
+	ScheduleKindCron     ScheduleKind = "cron"
+	ScheduleKindInterval ScheduleKind = "interval"
+)
+
+type Schedule struct {
+	ID           string       `json:"id"`
+	Name         string       `json:"name"`
+	ActionType   JobType      `json:"action_type"`
+	ActionParams string       `json:"action_params"`
+	Kind         ScheduleKind `json:"kind"`
+	Expression   string       `json:"expression"`
+	Enabled      bool         `json:"enabled"`
+	NextRunAt    time.Time    `json:"next_run_at"`
+	LastRunAt    time.Time    `json:"last_run_at"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+type ScheduleCore interface {
+	RegisterSchedule(ctx context.Context, name string, actionType JobType, actionParams string, kind ScheduleKind, expression string) (*Schedule, error)
+	EnableSchedule(ctx context.Context, name string, enabled bool) error
+	ListSchedules(ctx context.Context) ([]Schedule, error)
+	DeleteSchedule(ctx context.Context, name string) error
+	MaterializeSchedules(ctx context.Context, misfireGrace time.Duration) (int, error)
+	NextScheduledAt(ctx context.Context) (time.Time, error)
+}
+
+func NextRun(kind ScheduleKind, expression string, after time.Time) (time.Time, error) {
+	switch kind {
+	case ScheduleKindInterval:
+		interval, err := time.ParseDuration(expression)
+		if err != nil {
+			return time.Time{}, utils.WrapError(err, "invalid interval expression: %v", expression)
+		}
+		if interval <= 0 {
+			return time.Time{}, utils.NewError("interval must be positive: %v", expression)
+		}
+		return after.Add(interval), nil
+	case ScheduleKindCron:
+		schedule, err := cron.ParseStandard(expression)
+		if err != nil {
+			return time.Time{}, utils.WrapError(err, "invalid cron expression: %v", expression)
+		}
+		return schedule.Next(after.UTC()), nil
+	default:
+		return time.Time{}, utils.NewError("unknown schedule kind: %v", kind)
+	}
+}
+
+func ScheduledIdentifier(name string, runAt time.Time) string {
+	return "schedule:" + name + ":" + runAt.UTC().Format(time.RFC3339)
+}
+
+func (s *Scheduler) materializeSchedules() {
+	schedules, ok := s.Core.(ScheduleCore)
+	if !ok {
+		return
+	}
+	created, err := schedules.MaterializeSchedules(s.CancelableCtx, s.Config.Schedule.MisfireGrace)
+	if err != nil {
+		utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to materialize schedules")
+		return
+	}
+	if created > 0 {
+		utils.Log(utils.DebugLevel).BT().Send("Materialized %d scheduled jobs", created)
+	}
+}
+
+func (s *Scheduler) registerSchedules() {
+	schedules, ok := s.Core.(ScheduleCore)
+	if !ok {
+		if len(s.Config.Schedule.Definitions) > 0 {
+			utils.Log(utils.WarnLevel).BT().Send("Future core does not support schedules, ignoring %d definitions", len(s.Config.Schedule.Definitions))
+		}
+		return
+	}
+	s.disableRemovedSchedules(schedules)
+	for name, definition := range s.Config.Schedule.Definitions {
+		params := definition.ActionParams
+		if params == "" {
+			params = "{}"
+		}
+		_, err := schedules.RegisterSchedule(s.CancelableCtx, name, JobType(definition.ActionType), params, ScheduleKind(definition.Kind), definition.Expression)
+		if err != nil {
+			utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to register schedule: %s", name)
+		}
+	}
+}
+
+// disableRemovedSchedules keeps schedules dropped from the config from running; they stay listed for the admin API.
+func (s *Scheduler) disableRemovedSchedules(schedules ScheduleCore) {
+	registered, err := schedules.ListSchedules(s.CancelableCtx)
+	if err != nil {
+		utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to list schedules")
+		return
+	}
+	for _, schedule := range registered {
+		if _, ok := s.Config.Schedule.Definitions[schedule.Name]; ok || !schedule.Enabled {
+			continue
+		}
+		if err := schedules.EnableSchedule(s.CancelableCtx, schedule.Name, false); err != nil {
+			utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to disable removed schedule: %s", schedule.Name)
+			continue
+		}
+		utils.Log(utils.InfoLevel).BT().Send("Disabled schedule %s that is no longer defined", schedule.Name)
+	}
+}
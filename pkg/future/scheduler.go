@@ -2,29 +2,50 @@ package future
 
 import (
 	"context"
+	"errors"
 	"sync"
-	"time"
 
+	"github.com/benbjohnson/clock"
 	"github.com/solutionchallenge/ondaum-server/pkg/utils"
 )
 
 type Scheduler struct {
 	Core
 	Config        Config
+	Clock         clock.Clock
 	Handlers      map[JobType]Handler
 	QuitSignal    chan struct{}
+	QuitOnce      sync.Once
+	WakeSignal    chan struct{}
 	WaitGroup     sync.WaitGroup
+	Workers       sync.WaitGroup
+	Slots         chan struct{}
+	TypeSlots     map[JobType]chan struct{}
+	AlertSinks    []AlertSink
+	Running       map[string]context.CancelCauseFunc
+	RunningMutex  sync.Mutex
 	CancelableCtx context.Context
 	CancelFunc    context.CancelFunc
 }
 
-func NewScheduler(config Config, core Core) *Scheduler {
+func NewScheduler(config Config, core Core, clk clock.Clock) *Scheduler {
 	ctx, cancel := context.WithCancel(context.Background())
+	typeSlots := make(map[JobType]chan struct{})
+	for actionType, limit := range config.Worker.Limits {
+		if limit > 0 {
+			typeSlots[JobType(actionType)] = make(chan struct{}, limit)
+		}
+	}
 	return &Scheduler{
 		Core:          core,
 		Config:        config,
+		Clock:         clk,
 		Handlers:      make(map[JobType]Handler),
 		QuitSignal:    make(chan struct{}),
+		WakeSignal:    make(chan struct{}, 1),
+		Slots:         make(chan struct{}, max(config.Worker.Concurrency, 1)),
+		TypeSlots:     typeSlots,
+		Running:       make(map[string]context.CancelCauseFunc),
 		CancelableCtx: ctx,
 		CancelFunc:    cancel,
 	}
@@ -39,81 +60,177 @@ func (s *Scheduler) AddHandler(actionType JobType, handler Handler) {
 }
 
 func (s *Scheduler) Start() {
+	s.registerSchedules()
+	s.startReaper()
 	s.WaitGroup.Add(1)
 	go func() {
 		defer s.WaitGroup.Done()
 		for {
+			timer := s.Clock.Timer(s.nextSleep())
 			select {
-			case <-s.CancelableCtx.Done():
+			case <-s.QuitSignal:
+				timer.Stop()
 				utils.Log(utils.InfoLevel).BT().Send("Scheduler is shutting down...")
 				return
-			case <-time.After(s.Config.ScheduleCycle):
-				s.processJobs()
+			case <-s.Core.Wakeup():
+				timer.Stop()
+			case <-s.WakeSignal:
+				timer.Stop()
+			case <-timer.C:
 			}
+			s.materializeSchedules()
+			s.releaseWaiting()
+			s.processJobs()
 		}
 	}()
 }
 
-func (s *Scheduler) Stop() {
+func (s *Scheduler) Stop(ctx context.Context) error {
 	utils.Log(utils.InfoLevel).BT().Send("Initiating scheduler shutdown...")
-	s.CancelFunc()
+	s.QuitOnce.Do(func() {
+		close(s.QuitSignal)
+	})
 	s.WaitGroup.Wait()
-	utils.Log(utils.InfoLevel).BT().Send("Scheduler shutdown completed")
+
+	drained := make(chan struct{})
+	go func() {
+		s.Workers.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		s.CancelFunc()
+		utils.Log(utils.InfoLevel).BT().Send("Scheduler shutdown completed")
+		return nil
+	case <-ctx.Done():
+		s.CancelFunc()
+		utils.Log(utils.WarnLevel).BT().Send("Scheduler shutdown timed out, canceled running jobs")
+		return utils.WrapError(ctx.Err(), "failed to drain scheduler workers")
+	}
 }
 
 func (s *Scheduler) processJobs() {
 	for {
 		select {
-		case <-s.CancelableCtx.Done():
+		case <-s.QuitSignal:
 			utils.Log(utils.InfoLevel).BT().Send("Scheduler is stopping, exiting burst mode...")
 			return
-		default:
-			job, tx, err := s.Core.RunNext(s.CancelableCtx, false)
-			if err != nil {
-				utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to get next job")
-				return
-			}
-			if job == nil {
-				return
-			}
-			if tx == nil {
-				utils.Log(utils.WarnLevel).BT().Send("No transaction found for job: %s", job.ID)
-				s.Core.Cancel(s.CancelableCtx, job.ID)
-				continue
-			}
+		case s.Slots <- struct{}{}:
+		}
 
-			handler, ok := s.Handlers[job.ActionType]
-			if !ok {
-				utils.Log(utils.WarnLevel).BT().Send("Failed to get handler for action type: %s", job.ActionType)
-				tx.Fail(s.CancelableCtx, "Failed to get handler for action type")
-				continue
-			}
+		job, tx, err := s.Core.RunNext(s.CancelableCtx, false, s.saturatedTypes()...)
+		if err != nil {
+			<-s.Slots
+			utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to get next job")
+			return
+		}
+		if job == nil {
+			<-s.Slots
+			return
+		}
 
-			func() {
-				defer func() {
-					if r := recover(); r != nil {
-						errMsg := "Handler panicked"
-						if err, ok := r.(error); ok {
-							errMsg = err.Error()
-						}
-						utils.Log(utils.ErrorLevel).Err(utils.NewError("%v", r)).BT().Send("Handler panicked for job: %s", job.ID)
-						tx.Fail(s.CancelableCtx, errMsg)
-					}
-				}()
-
-				err = handler.Handle(s.CancelableCtx, job)
-				if err != nil {
-					utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to handle job")
-					tx.Fail(s.CancelableCtx, err.Error())
-					return
+		typeSlots, limited := s.TypeSlots[job.ActionType]
+		if limited {
+			typeSlots <- struct{}{}
+		}
+		s.Workers.Add(1)
+		go func() {
+			defer s.Workers.Done()
+			defer func() {
+				if limited {
+					<-typeSlots
 				}
-				tx.Complete(s.CancelableCtx)
+				<-s.Slots
+				s.Wake()
 			}()
+			s.runJob(job, tx)
+		}()
+	}
+}
+
+func (s *Scheduler) saturatedTypes() []JobType {
+	saturated := []JobType{}
+	for actionType, slots := range s.TypeSlots {
+		if len(slots) == cap(slots) {
+			saturated = append(saturated, actionType)
+		}
+	}
+	return saturated
+}
 
-			if s.Config.DeleteAfterCompletion {
-				utils.Log(utils.DebugLevel).BT().Send("Job completed: %s", job.ID)
-				s.Core.DeletePermanently(s.CancelableCtx, job.ID)
+func (s *Scheduler) runJob(job *Job, tx Transaction) {
+	if tx == nil {
+		utils.Log(utils.WarnLevel).BT().Send("No transaction found for job: %s", job.ID)
+		s.Core.Cancel(s.CancelableCtx, job.ID)
+		return
+	}
+
+	handler, ok := s.Handlers[job.ActionType]
+	if !ok {
+		utils.Log(utils.WarnLevel).BT().Send("Failed to get handler for action type: %s", job.ActionType)
+		s.deadLetter(job, tx, FailureReasonError, utils.NewError("failed to get handler for action type: %s", job.ActionType))
+		return
+	}
+
+	timeout := s.TimeoutOf(handler)
+	ctx, cancel := s.track(job, timeout)
+	defer cancel(nil)
+
+	stopHeartbeat := s.startHeartbeat(job, tx, cancel)
+	defer stopHeartbeat()
+
+	completed := func() (completed bool) {
+		defer func() {
+			if r := recover(); r != nil {
+				err, ok := r.(error)
+				if !ok {
+					err = utils.NewError("Handler panicked: %v", r)
+				}
+				utils.Log(utils.ErrorLevel).Err(err).BT().Send("Handler panicked for job: %s", job.ID)
+				s.failJob(job, tx, handler, FailureReasonPanic, err)
 			}
+		}()
+
+		err := handler.Handle(ctx, job)
+		if interrupted(ctx) {
+			utils.Log(utils.InfoLevel).Err(context.Cause(ctx)).BT().Send("Job was interrupted while running: %s", job.ID)
+			return false
+		}
+		if err != nil {
+			reason := FailureReasonError
+			if errors.Is(context.Cause(ctx), ErrJobTimeout) {
+				reason = FailureReasonTimeout
+				err = utils.WrapError(err, "job timed out after %v", timeout)
+			}
+			utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to handle job")
+			s.failJob(job, tx, handler, reason, err)
+			return false
+		}
+		if err := tx.Complete(s.CancelableCtx, job.Output, job.Successors); err != nil {
+			utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to mark job as completed: %s", job.ID)
+			return false
+		}
+		return true
+	}()
+
+	if completed {
+		s.releaseWaiting()
+	}
+	if completed && s.Config.DeleteAfterCompletion {
+		utils.Log(utils.DebugLevel).BT().Send("Job completed: %s", job.ID)
+		s.Core.DeletePermanently(s.CancelableCtx, job.ID)
+	}
+}
+
+func (s *Scheduler) failJob(job *Job, tx Transaction, handler Handler, reason FailureReason, cause error) {
+	policy := s.RetryPolicyOf(job.ActionType)
+	if IsRetryable(handler, cause) && policy.ShouldRetry(job.Attempts) {
+		retryAfter := policy.Backoff(job.Attempts)
+		utils.Log(utils.InfoLevel).BT().Send("Retrying job %s (attempt %d/%d) after %v", job.ID, job.Attempts, policy.MaxAttempts, retryAfter)
+		if err := tx.Retry(s.CancelableCtx, reason, cause.Error(), retryAfter); err != nil {
+			utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to reschedule job for retry: %s", job.ID)
 		}
+		return
 	}
+	s.deadLetter(job, tx, reason, cause)
 }
@@ -0,0 +1,169 @@
+package future_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/solutionchallenge/ondaum-server/pkg/future"
+	"github.com/solutionchallenge/ondaum-server/pkg/future/memory"
+)
+
+var testcases_Scheduler_Slots = []struct {
+	name     string
+	config   future.WorkerConfig
+	jobs     []future.JobType
+	running  map[future.JobType]int
+	peak     map[future.JobType]int
+	expected int
+}{
+	{
+		name:     "Success Case - Concurrency Limit",
+		config:   future.WorkerConfig{Concurrency: 2},
+		jobs:     []future.JobType{"free", "free", "free", "free"},
+		running:  map[future.JobType]int{"free": 2},
+		peak:     map[future.JobType]int{"free": 2},
+		expected: 2,
+	},
+	{
+		name:     "Success Case - Type Limit Leaves Room For Others",
+		config:   future.WorkerConfig{Concurrency: 3, Limits: map[string]int{"limited": 1}},
+		jobs:     []future.JobType{"limited", "limited", "limited", "free", "free", "free"},
+		running:  map[future.JobType]int{"limited": 1, "free": 2},
+		peak:     map[future.JobType]int{"limited": 1},
+		expected: 3,
+	},
+}
+
+func Test_Scheduler_Slots(t *testing.T) {
+	for _, testcase := range testcases_Scheduler_Slots {
+		t.Run(testcase.name, func(t *testing.T) {
+			tester := prepareSchedulerForTest(testcase.config)
+			for _, actionType := range testcase.jobs {
+				tester.scheduler.AddHandler(actionType, tester.handler)
+				if _, err := tester.scheduler.Create(context.Background(), actionType, "{}", 0); err != nil {
+					t.Fatalf("failed to create job: %v", err)
+				}
+			}
+			tester.scheduler.Start()
+			defer tester.scheduler.Stop(context.Background())
+			defer tester.handler.release()
+
+			tester.handler.awaitRunning(t, testcase.expected)
+			time.Sleep(100 * time.Millisecond)
+			tester.handler.expectRunning(t, testcase.running)
+
+			tester.handler.release()
+			tester.handler.awaitCompleted(t, len(testcase.jobs))
+			tester.handler.expectPeak(t, testcase.peak)
+		})
+	}
+}
+
+type tester_Scheduler struct {
+	scheduler *future.Scheduler
+	handler   *tester_SlotHandler
+}
+
+func prepareSchedulerForTest(config future.WorkerConfig) *tester_Scheduler {
+	core := memory.NewCore(clock.New(), "tester", time.Minute)
+	scheduler := future.NewScheduler(future.Config{
+		ScheduleCycle: 50 * time.Millisecond,
+		Worker:        config,
+	}, core, clock.New())
+	return &tester_Scheduler{
+		scheduler: scheduler,
+		handler: &tester_SlotHandler{
+			Running: make(map[future.JobType]int),
+			Peak:    make(map[future.JobType]int),
+			Release: make(chan struct{}),
+		},
+	}
+}
+
+type tester_SlotHandler struct {
+	Running   map[future.JobType]int
+	Peak      map[future.JobType]int
+	Completed int
+	Release   chan struct{}
+	Released  sync.Once
+	Mutex     sync.Mutex
+}
+
+func (handler *tester_SlotHandler) release() {
+	handler.Released.Do(func() { close(handler.Release) })
+}
+
+func (handler *tester_SlotHandler) Handle(ctx context.Context, job *future.Job) error {
+	handler.Mutex.Lock()
+	handler.Running[job.ActionType]++
+	handler.Peak[job.ActionType] = max(handler.Peak[job.ActionType], handler.Running[job.ActionType])
+	handler.Mutex.Unlock()
+
+	<-handler.Release
+
+	handler.Mutex.Lock()
+	handler.Running[job.ActionType]--
+	handler.Completed++
+	handler.Mutex.Unlock()
+	return nil
+}
+
+func (handler *tester_SlotHandler) awaitRunning(t *testing.T, expected int) {
+	t.Helper()
+	handler.await(t, func() bool {
+		running := 0
+		for _, count := range handler.Running {
+			running += count
+		}
+		return running >= expected
+	})
+}
+
+func (handler *tester_SlotHandler) awaitCompleted(t *testing.T, expected int) {
+	t.Helper()
+	handler.await(t, func() bool {
+		return handler.Completed >= expected
+	})
+}
+
+func (handler *tester_SlotHandler) await(t *testing.T, done func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		handler.Mutex.Lock()
+		ok := done()
+		handler.Mutex.Unlock()
+		if ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for jobs, running %v", handler.Running)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (handler *tester_SlotHandler) expectRunning(t *testing.T, expected map[future.JobType]int) {
+	t.Helper()
+	handler.Mutex.Lock()
+	defer handler.Mutex.Unlock()
+	for actionType, count := range expected {
+		if handler.Running[actionType] != count {
+			t.Fatalf("expected %d %s jobs running, got %v", count, actionType, handler.Running)
+		}
+	}
+}
+
+func (handler *tester_SlotHandler) expectPeak(t *testing.T, expected map[future.JobType]int) {
+	t.Helper()
+	handler.Mutex.Lock()
+	defer handler.Mutex.Unlock()
+	for actionType, count := range expected {
+		if handler.Peak[actionType] != count {
+			t.Fatalf("expected at most %d %s jobs at once, got %v", count, actionType, handler.Peak)
+		}
+	}
+}
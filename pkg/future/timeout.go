@@ -0,0 +1,59 @@
+package future
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrJobTimeout  = errors.New("future job timed out")
+	ErrJobCanceled = errors.New("future job canceled")
+)
+
+// TimeoutDeclarer can be implemented by a Handler to bound how long a single attempt may run.
+type TimeoutDeclarer interface {
+	Timeout() time.Duration
+}
+
+func (s *Scheduler) TimeoutOf(handler Handler) time.Duration {
+	if declarer, ok := handler.(TimeoutDeclarer); ok && declarer.Timeout() > 0 {
+		return declarer.Timeout()
+	}
+	return s.Config.Worker.Timeout
+}
+
+func (s *Scheduler) CancelRunning(ID string) bool {
+	s.RunningMutex.Lock()
+	cancel, ok := s.Running[ID]
+	s.RunningMutex.Unlock()
+	if ok {
+		cancel(ErrJobCanceled)
+	}
+	return ok
+}
+
+func (s *Scheduler) track(job *Job, timeout time.Duration) (context.Context, context.CancelCauseFunc) {
+	ctx, cancel := context.WithCancelCause(s.CancelableCtx)
+	stop := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, stop = context.WithTimeoutCause(ctx, timeout, ErrJobTimeout)
+	}
+
+	s.RunningMutex.Lock()
+	s.Running[job.ID] = cancel
+	s.RunningMutex.Unlock()
+
+	return ctx, func(cause error) {
+		s.RunningMutex.Lock()
+		delete(s.Running, job.ID)
+		s.RunningMutex.Unlock()
+		cancel(cause)
+		stop()
+	}
+}
+
+func interrupted(ctx context.Context) bool {
+	cause := context.Cause(ctx)
+	return errors.Is(cause, ErrJobCanceled) || errors.Is(cause, ErrLeaseLost)
+}
@@ -0,0 +1,52 @@
+package future
+
+import (
+	"time"
+
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+)
+
+const (
+	DefaultScheduleCycle = 1 * time.Minute
+	MinimumScheduleSleep = 1 * time.Second
+)
+
+// Signal wakes up whoever listens on the channel without blocking; pending signals coalesce into one.
+func Signal(wakeup chan struct{}) {
+	select {
+	case wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) Wake() {
+	Signal(s.WakeSignal)
+}
+
+func (s *Scheduler) nextSleep() time.Duration {
+	cycle := s.Config.ScheduleCycle
+	if cycle <= 0 {
+		cycle = DefaultScheduleCycle
+	}
+	sleep := cycle
+	now := s.Clock.Now().UTC()
+
+	next, err := s.Core.NextTriggerAt(s.CancelableCtx)
+	if err != nil {
+		utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to get next trigger of future jobs")
+	} else if !next.IsZero() {
+		sleep = min(sleep, next.Sub(now))
+	}
+
+	if schedules, ok := s.Core.(ScheduleCore); ok {
+		next, err := schedules.NextScheduledAt(s.CancelableCtx)
+		if err != nil {
+			utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to get next run of schedules")
+		} else if !next.IsZero() {
+			sleep = min(sleep, next.Sub(now))
+		}
+	}
+
+	// Due work that could not be claimed (saturated types, other replicas) must not turn the loop into a spin.
+	return max(sleep, min(cycle, MinimumScheduleSleep))
+}
@@ -0,0 +1,75 @@
+package future
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+)
+
+// JobSpec describes a job to be created later, either as a successor of a completed job or as a fan-in step.
+// A successor without ActionParams receives the output of the job it follows.
+type JobSpec struct {
+	ActionType   JobType       `json:"action_type"`
+	ActionParams string        `json:"action_params,omitempty"`
+	TriggerAfter time.Duration `json:"trigger_after,omitempty"`
+	Successors   []JobSpec     `json:"successors,omitempty"`
+}
+
+func (s JobSpec) ParamsFrom(output string) string {
+	switch {
+	case s.ActionParams != "":
+		return s.ActionParams
+	case output != "":
+		return output
+	default:
+		return "{}"
+	}
+}
+
+type WorkflowStatus struct {
+	ID     string            `json:"id"`
+	Status JobStatus         `json:"status"`
+	Total  int               `json:"total"`
+	Counts map[JobStatus]int `json:"counts"`
+}
+
+func NewWorkflowStatus(ID string, statuses []JobStatus) *WorkflowStatus {
+	workflow := &WorkflowStatus{
+		ID:     ID,
+		Status: JobStatusCompleted,
+		Total:  len(statuses),
+		Counts: make(map[JobStatus]int),
+	}
+	for _, status := range statuses {
+		workflow.Counts[status]++
+	}
+	// A dead-lettered or canceled step blocks everything after it, so it outranks steps still in flight.
+	for _, status := range []JobStatus{JobStatusDeadLettered, JobStatusFailed, JobStatusCanceled, JobStatusRunning, JobStatusPending, JobStatusWaiting} {
+		if workflow.Counts[status] > 0 {
+			workflow.Status = status
+			break
+		}
+	}
+	return workflow
+}
+
+// BlocksDependents reports whether a job ended without completing, so fan-in jobs waiting on it can never run.
+func BlocksDependents(status JobStatus) bool {
+	return status == JobStatusDeadLettered || status == JobStatusFailed || status == JobStatusCanceled
+}
+
+func AbandonedError(dependencyID string, status JobStatus) string {
+	return fmt.Sprintf("dependency %s was %s", dependencyID, status)
+}
+
+func (s *Scheduler) releaseWaiting() {
+	released, err := s.Core.ReleaseWaiting(s.CancelableCtx)
+	if err != nil {
+		utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to release waiting jobs")
+		return
+	}
+	if released > 0 {
+		utils.Log(utils.DebugLevel).BT().Send("Released %d waiting jobs", released)
+	}
+}
@@ -19,4 +19,10 @@ type Config struct {
 		Idle     time.Duration `mapstructure:"idle" default:"10s"`
 		Shutdown time.Duration `mapstructure:"shutdown" default:"10s"`
 	} `mapstructure:"timeout"`
+	Admin AdminConfig `mapstructure:"admin"`
+}
+
+// AdminConfig guards the operator routes that change data; an empty token locks them entirely.
+type AdminConfig struct {
+	Token string `mapstructure:"token"`
 }
@@ -1,6 +1,9 @@
 package http
 
 import (
+	"crypto/subtle"
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/solutionchallenge/ondaum-server/pkg/jwt"
 	"github.com/solutionchallenge/ondaum-server/pkg/utils"
@@ -8,6 +11,8 @@ import (
 
 type MiddlewareFunc = func(c *fiber.Ctx) error
 
+const AdminTokenHeader = "X-Admin-Token"
+
 func NewJWTAuthMiddleware(generator jwt.Generator) MiddlewareFunc {
 	return func(c *fiber.Ctx) error {
 		rid := GetRequestID(c)
@@ -44,7 +49,28 @@ func NewJWTAuthMiddleware(generator jwt.Generator) MiddlewareFunc {
 		utils.Log(utils.InfoLevel).Ctx(c.UserContext()).RID(rid).BT().Send("Unpacked User: %v (%+v)", claims.Value, claims.Metadata)
 		c.Locals("X-User-Metadata", claims.Metadata)
 		c.Locals("X-User-ID", claims.Value)
+		if claims.ExpiresAt != nil {
+			c.Locals("X-User-Token-Expiry", claims.ExpiresAt.Time)
+		}
+
+		return c.Next()
+	}
+}
 
+func NewAdminGuardMiddleware(config AdminConfig) MiddlewareFunc {
+	return func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+		token := c.Get(AdminTokenHeader)
+		if token == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(
+				NewError(ctx, errors.New("missing admin token"), "Admin token is required"),
+			)
+		}
+		if config.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(config.Token)) != 1 {
+			return c.Status(fiber.StatusForbidden).JSON(
+				NewError(ctx, errors.New("invalid admin token"), "Admin token is not allowed"),
+			)
+		}
 		return c.Next()
 	}
 }
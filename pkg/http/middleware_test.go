@@ -0,0 +1,62 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var testcases_AdminGuardMiddleware = []struct {
+	name       string
+	config     AdminConfig
+	token      string
+	statusCode int
+}{
+	{
+		name:       "Success Case - Matching Token",
+		config:     AdminConfig{Token: "operator"},
+		token:      "operator",
+		statusCode: http.StatusOK,
+	},
+	{
+		name:       "Failure Case - Missing Token",
+		config:     AdminConfig{Token: "operator"},
+		statusCode: http.StatusUnauthorized,
+	},
+	{
+		name:       "Failure Case - Wrong Token",
+		config:     AdminConfig{Token: "operator"},
+		token:      "guest",
+		statusCode: http.StatusForbidden,
+	},
+	{
+		name:       "Failure Case - No Token Configured",
+		token:      "operator",
+		statusCode: http.StatusForbidden,
+	},
+}
+
+func Test_AdminGuardMiddleware(t *testing.T) {
+	for _, testcase := range testcases_AdminGuardMiddleware {
+		t.Run(testcase.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Post("/", NewAdminGuardMiddleware(testcase.config), func(c *fiber.Ctx) error {
+				return c.SendStatus(http.StatusOK)
+			})
+
+			request := httptest.NewRequest(http.MethodPost, "/", nil)
+			if testcase.token != "" {
+				request.Header.Set(AdminTokenHeader, testcase.token)
+			}
+			response, err := app.Test(request)
+			if err != nil {
+				t.Fatalf("failed to send request: %v", err)
+			}
+			if response.StatusCode != testcase.statusCode {
+				t.Fatalf("expected status %d, got %d", testcase.statusCode, response.StatusCode)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -41,3 +42,11 @@ func GetUserMetadata(c *fiber.Ctx) (map[string]any, error) {
 	}
 	return userMetadata, nil
 }
+
+func GetTokenExpiry(c *fiber.Ctx) (time.Time, error) {
+	expiry, ok := c.Locals("X-User-Token-Expiry").(time.Time)
+	if !ok {
+		return time.Time{}, utils.NewError("X-User-Token-Expiry is not set")
+	}
+	return expiry, nil
+}
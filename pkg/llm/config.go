@@ -1,5 +1,7 @@
 package llm
 
+import "time"
+
 type Config struct {
 	Gemini GenericConfig `mapstructure:"gemini"`
 }
@@ -28,6 +30,13 @@ type RedactionThreshold struct {
 	CivicIntegrity   string `mapstructure:"civic_integrity"`
 }
 
+type ContextCacheConfig struct {
+	Enabled       bool          `mapstructure:"enabled" default:"false"`
+	TTL           time.Duration `mapstructure:"ttl" default:"1h"`
+	RefreshBefore time.Duration `mapstructure:"refresh_before" default:"5m"`
+	FailureTTL    time.Duration `mapstructure:"failure_ttl" default:"10m"`
+}
+
 type GenericConfig struct {
 	Enabled            bool               `mapstructure:"enabled"`
 	APIKey             string             `mapstructure:"api_key"`
@@ -36,4 +45,5 @@ type GenericConfig struct {
 	ResponseFormat     string             `mapstructure:"response_format"`
 	PreparedPrompts    []PreparedPrompt   `mapstructure:"prepared_prompts"`
 	RedactionThreshold RedactionThreshold `mapstructure:"redaction_threshold"`
+	ContextCache       ContextCacheConfig `mapstructure:"context_cache"`
 }
@@ -0,0 +1,150 @@
+package gemini
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	"google.golang.org/genai"
+)
+
+const (
+	DefaultContextCacheTTL           = 1 * time.Hour
+	DefaultContextCacheRefreshBefore = 5 * time.Minute
+	DefaultContextCacheFailureTTL    = 10 * time.Minute
+)
+
+type CachedContext struct {
+	Name     string
+	ExpireAt time.Time
+	// A prompt below the minimum cacheable size fails the same way every time, so failures are remembered for a while.
+	FailedUntil time.Time
+	Mutex       sync.Mutex
+}
+
+func ApplyContextCache(
+	ctx context.Context, client *Client, key string, config *genai.GenerateContentConfig,
+	prefix func(ctx context.Context) ([]*genai.Content, error),
+) bool {
+	if !client.Config.Gemini.ContextCache.Enabled {
+		return false
+	}
+	name, hit := resolveContextCache(ctx, client, key, config.SystemInstruction, prefix)
+	client.Mutex.Lock()
+	if hit {
+		client.Statistics.CacheHits++
+	} else {
+		client.Statistics.CacheMisses++
+	}
+	client.Mutex.Unlock()
+	if name == "" {
+		return false
+	}
+	config.CachedContent = name
+	config.SystemInstruction = nil
+	return true
+}
+
+func resolveContextCache(
+	ctx context.Context, client *Client, key string, instruction *genai.Content,
+	prefix func(ctx context.Context) ([]*genai.Content, error),
+) (string, bool) {
+	ttl := client.Config.Gemini.ContextCache.TTL
+	if ttl <= 0 {
+		ttl = DefaultContextCacheTTL
+	}
+	refreshBefore := client.Config.Gemini.ContextCache.RefreshBefore
+	if refreshBefore <= 0 || refreshBefore >= ttl {
+		refreshBefore = min(DefaultContextCacheRefreshBefore, ttl/2)
+	}
+	failureTTL := client.Config.Gemini.ContextCache.FailureTTL
+	if failureTTL <= 0 {
+		failureTTL = DefaultContextCacheFailureTTL
+	}
+
+	client.CacheMutex.Lock()
+	cached, ok := client.CachedContexts[key]
+	if !ok {
+		cached = &CachedContext{}
+		client.CachedContexts[key] = cached
+	}
+	client.CacheMutex.Unlock()
+
+	// Only callers of the same key wait on each other while the cache is refreshed or created.
+	cached.Mutex.Lock()
+	defer cached.Mutex.Unlock()
+
+	now := time.Now().UTC()
+	if now.Before(cached.FailedUntil) {
+		return "", false
+	}
+	if cached.Name != "" && now.Before(cached.ExpireAt.Add(-refreshBefore)) {
+		return cached.Name, true
+	}
+	if cached.Name != "" && now.Before(cached.ExpireAt) {
+		updated, err := client.Core.Caches.Update(ctx, cached.Name, &genai.UpdateCachedContentConfig{TTL: ttl})
+		if err == nil {
+			cached.ExpireAt = expireTimeOf(updated, now, ttl)
+			utils.Log(utils.DebugLevel).BT().Send("Refreshed context cache %s for %s until %v", cached.Name, key, cached.ExpireAt)
+			return cached.Name, true
+		}
+		utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to refresh context cache %s for %s", cached.Name, key)
+	}
+	cached.Name = ""
+	cached.ExpireAt = time.Time{}
+
+	contents := []*genai.Content(nil)
+	if prefix != nil {
+		prepared, err := prefix(ctx)
+		if err != nil {
+			cached.FailedUntil = now.Add(failureTTL)
+			utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to prepare context cache contents for %s, skipping until %v", key, cached.FailedUntil)
+			return "", false
+		}
+		contents = prepared
+	}
+	if instruction == nil && len(contents) == 0 {
+		return "", false
+	}
+
+	created, err := client.Core.Caches.Create(ctx, client.Config.Gemini.LLMModel, &genai.CreateCachedContentConfig{
+		TTL:               ttl,
+		DisplayName:       "ondaum-" + key,
+		SystemInstruction: instruction,
+		Contents:          contents,
+	})
+	if err != nil {
+		cached.FailedUntil = now.Add(failureTTL)
+		utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to create context cache for %s, skipping until %v", key, cached.FailedUntil)
+		return "", false
+	}
+	cached.Name = created.Name
+	cached.ExpireAt = expireTimeOf(created, now, ttl)
+	utils.Log(utils.InfoLevel).BT().Send("Created context cache %s for %s", created.Name, key)
+	return created.Name, false
+}
+
+func releaseContextCaches(ctx context.Context, client *Client) {
+	client.CacheMutex.Lock()
+	released := client.CachedContexts
+	client.CachedContexts = make(map[string]*CachedContext)
+	client.CacheMutex.Unlock()
+
+	for key, cached := range released {
+		cached.Mutex.Lock()
+		if cached.Name != "" {
+			if _, err := client.Core.Caches.Delete(ctx, cached.Name, nil); err != nil {
+				utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to delete context cache %s for %s", cached.Name, key)
+			}
+		}
+		cached.Mutex.Unlock()
+	}
+}
+
+func expireTimeOf(cached *genai.CachedContent, now time.Time, ttl time.Duration) time.Time {
+	if cached != nil && !cached.ExpireTime.IsZero() {
+		return cached.ExpireTime
+	}
+	return now.Add(ttl)
+}
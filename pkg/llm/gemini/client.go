@@ -18,6 +18,9 @@ type Client struct {
 	Conversations map[string]llm.Conversation
 	Statistics    llm.Statistics
 	Mutex         sync.Mutex
+
+	CachedContexts map[string]*CachedContext
+	CacheMutex     sync.Mutex
 }
 
 func NewClient(config llm.Config) (*Client, error) {
@@ -35,6 +38,8 @@ func NewClient(config llm.Config) (*Client, error) {
 		Config:        config,
 		Core:          core,
 		Conversations: make(map[string]llm.Conversation),
+
+		CachedContexts: make(map[string]*CachedContext),
 	}, nil
 }
 
@@ -87,22 +92,36 @@ func (client *Client) RunActionPrompt(ctx context.Context, instructionIdentifier
 
 	currentUserTurnParts := []*genai.Part{genai.NewPartFromText(prompt)}
 	if prepared.AttachmentFile != "" {
-		reader, err := utils.OpenFileFrom(prepared.AttachmentFile)
-		if err != nil {
-			return llm.Message{}, utils.WrapError(err, "OpenFileFrom failed for %s", prepared.AttachmentFile)
+		var uploaded *genai.Part
+		upload := func(ctx context.Context) (*genai.Part, error) {
+			if uploaded != nil {
+				return uploaded, nil
+			}
+			fileDataPart, err := uploadAttachment(ctx, client, prepared)
+			if err != nil {
+				return nil, err
+			}
+			uploaded = fileDataPart
+			return uploaded, nil
 		}
-		defer reader.Close()
-
-		uploadedFile, uploadErr := client.Core.Files.Upload(ctx, reader, &genai.UploadFileConfig{
-			MIMEType:    prepared.AttachmentMime,
-			DisplayName: prepared.AttachmentFile,
-		})
-		if uploadErr != nil {
-			return llm.Message{}, utils.WrapError(uploadErr, "file upload failed for %s", prepared.AttachmentFile)
+		cached := ApplyContextCache(ctx, client, instructionIdentifier+"/"+promptIdentifier, config,
+			func(ctx context.Context) ([]*genai.Content, error) {
+				fileDataPart, err := upload(ctx)
+				if err != nil {
+					return nil, err
+				}
+				return []*genai.Content{genai.NewContentFromParts([]*genai.Part{fileDataPart}, genai.RoleUser)}, nil
+			},
+		)
+		if !cached {
+			fileDataPart, err := upload(ctx)
+			if err != nil {
+				return llm.Message{}, err
+			}
+			currentUserTurnParts = append(currentUserTurnParts, fileDataPart)
 		}
-
-		fileDataPart := genai.NewPartFromURI(uploadedFile.URI, prepared.AttachmentMime)
-		currentUserTurnParts = append(currentUserTurnParts, fileDataPart)
+	} else {
+		ApplyContextCache(ctx, client, instructionIdentifier, config, nil)
 	}
 
 	currentUserTurnContent := genai.NewContentFromParts(currentUserTurnParts, genai.RoleUser)
@@ -137,6 +156,24 @@ func (client *Client) RunActionPrompt(ctx context.Context, instructionIdentifier
 	}, nil
 }
 
+func uploadAttachment(ctx context.Context, client *Client, prepared *llm.PreparedPrompt) (*genai.Part, error) {
+	reader, err := utils.OpenFileFrom(prepared.AttachmentFile)
+	if err != nil {
+		return nil, utils.WrapError(err, "OpenFileFrom failed for %s", prepared.AttachmentFile)
+	}
+	defer reader.Close()
+
+	uploadedFile, err := client.Core.Files.Upload(ctx, reader, &genai.UploadFileConfig{
+		MIMEType:    prepared.AttachmentMime,
+		DisplayName: prepared.AttachmentFile,
+	})
+	if err != nil {
+		return nil, utils.WrapError(err, "file upload failed for %s", prepared.AttachmentFile)
+	}
+
+	return genai.NewPartFromURI(uploadedFile.URI, prepared.AttachmentMime), nil
+}
+
 func (client *Client) GetStatistics() llm.Statistics {
 	client.Mutex.Lock()
 	defer client.Mutex.Unlock()
@@ -144,6 +181,9 @@ func (client *Client) GetStatistics() llm.Statistics {
 }
 
 func (client *Client) Close(ids ...string) error {
+	if len(ids) <= 0 {
+		releaseContextCaches(context.Background(), client)
+	}
 	client.Mutex.Lock()
 	defer client.Mutex.Unlock()
 	if len(ids) <= 0 {
@@ -29,6 +29,7 @@ func NewConversation(ctx context.Context, id string, client *Client, prompt stri
 	if err != nil {
 		return nil, utils.WrapError(err, "failed to build generative config")
 	}
+	ApplyContextCache(ctx, client, prompt, config, nil)
 	session, err := client.Core.Chats.Create(
 		ctx,
 		client.Config.Gemini.LLMModel,
@@ -48,7 +49,9 @@ func NewConversation(ctx context.Context, id string, client *Client, prompt stri
 }
 
 func (conversation *Conversation) Request(ctx context.Context, request llm.Message) (llm.Message, error) {
-	conversation.Manager.Add(ctx, request)
+	if err := conversation.Manager.Add(ctx, request); err != nil {
+		return llm.Message{}, utils.WrapError(err, "failed to add request to history")
+	}
 
 	prompt := genai.NewPartFromText(request.Content)
 	response, err := conversation.Session.SendMessage(ctx, *prompt)
@@ -77,7 +80,9 @@ func (conversation *Conversation) Request(ctx context.Context, request llm.Messa
 			"feedbacks": feedbacks,
 		},
 	}
-	conversation.Manager.Add(ctx, message)
+	if err := conversation.Manager.Add(ctx, message); err != nil {
+		return llm.Message{}, utils.WrapError(err, "failed to add response to history")
+	}
 	return message, nil
 }
 
@@ -3,6 +3,6 @@ package llm
 import "context"
 
 type HistoryManager interface {
-	Add(ctx context.Context, messages ...Message)
+	Add(ctx context.Context, messages ...Message) error
 	Get(ctx context.Context, conversationID string) []Message
 }
@@ -6,6 +6,16 @@ type Statistics struct {
 	CompletionTokens int64
 	ThoughtsTokens   int64
 	CachedTokens     int64
+	CacheHits        int64
+	CacheMisses      int64
+}
+
+func (s Statistics) CacheHitRate() float64 {
+	total := s.CacheHits + s.CacheMisses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.CacheHits) / float64(total)
 }
 
 type Role string
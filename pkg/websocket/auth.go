@@ -0,0 +1,108 @@
+package websocket
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/solutionchallenge/ondaum-server/pkg/jwt"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+)
+
+const (
+	CloseCodeTokenExpired = 4004
+)
+
+type AuthenticatePayload struct {
+	AccessToken string `json:"access_token"`
+}
+
+type AuthStatePayload struct {
+	ExpiresAt   time.Time `json:"expires_at"`
+	ExpiresInMS int64     `json:"expires_in_ms"`
+}
+
+func BuildAuthStatePayload(expiresAt time.Time, now time.Time) AuthStatePayload {
+	return AuthStatePayload{
+		ExpiresAt:   expiresAt.UTC(),
+		ExpiresInMS: max(expiresAt.Sub(now), 0).Milliseconds(),
+	}
+}
+
+func handleAuthenticate(conn *Connection, generator jwt.Generator, request MessageWrapper) ResponseWrapper {
+	if generator == nil {
+		return BuildErrorResponse(request, RejectReasonUnauthorized, utils.NewError("authentication is not supported"))
+	}
+	payload, err := DecodePayload[AuthenticatePayload](request.Payload)
+	if err != nil {
+		return BuildErrorResponse(request, RejectReasonInvalidPayload, err)
+	}
+	if payload.AccessToken == "" {
+		return BuildErrorResponse(request, RejectReasonInvalidPayload, utils.NewError("access token is required"))
+	}
+	claims, err := generator.UnpackToken(payload.AccessToken)
+	if err != nil {
+		utils.Log(utils.InfoLevel).Err(err).CID(request.SessionID).RID(request.MessageID).BT().Send("Failed to unpack token")
+		return BuildErrorResponse(request, RejectReasonUnauthorized, err)
+	}
+	if claims.Type != jwt.AccessTokenType {
+		return BuildErrorResponse(request, RejectReasonUnauthorized, utils.NewError("required valid access token"))
+	}
+	if conn.UserID == 0 || claims.Value != strconv.FormatInt(conn.UserID, 10) {
+		utils.Log(utils.WarnLevel).CID(request.SessionID).RID(request.MessageID).BT().Send("Token subject %v does not match connection user %d", claims.Value, conn.UserID)
+		return BuildErrorResponse(request, RejectReasonUnauthorized, utils.NewError("token subject does not match connection"))
+	}
+
+	expiresAt := time.Time{}
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+	conn.SetTokenExpiry(expiresAt)
+	utils.Log(utils.InfoLevel).CID(request.SessionID).RID(request.MessageID).BT().Send("Renewed token until %v", expiresAt)
+	return BuildResponseFrom(request,
+		uuid.New().String(),
+		PredefinedActionAuthenticate, BuildAuthStatePayload(expiresAt, time.Now()),
+	)
+}
+
+func startAuthWatch(conn *Connection, config AuthConfig, sessionID string) func() {
+	if _, ok := conn.TokenExpiresAt(); !ok || config.CheckInterval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(config.CheckInterval)
+		defer ticker.Stop()
+		warnedFor := time.Time{}
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				expiresAt, ok := conn.TokenExpiresAt()
+				if !ok {
+					continue
+				}
+				if !now.Before(expiresAt) {
+					utils.Log(utils.InfoLevel).CID(sessionID).BT().Send("Reaping connection with expired token")
+					conn.Reap(sessionID, CloseCodeTokenExpired, "token expired")
+					return
+				}
+				if config.WarnBefore > 0 && expiresAt.Sub(now) <= config.WarnBefore && !warnedFor.Equal(expiresAt) {
+					warnedFor = expiresAt
+					warning := BuildPushResponse(sessionID, PredefinedActionAuthExpiring, BuildAuthStatePayload(expiresAt, now))
+					if err := conn.WriteResponse(warning); err != nil {
+						utils.Log(utils.WarnLevel).Err(err).CID(sessionID).BT().Send("Failed to send auth expiring warning")
+					}
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
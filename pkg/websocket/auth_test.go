@@ -0,0 +1,109 @@
+package websocket_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/solutionchallenge/ondaum-server/pkg/jwt"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
+	"github.com/solutionchallenge/ondaum-server/pkg/websocket/wstest"
+)
+
+var testcases_Authenticate = []struct {
+	name     string
+	config   wspkg.AuthConfig
+	expire   time.Duration
+	scenario func(t *testing.T, tester *tester_Authenticate, client *wstest.Client)
+}{
+	{
+		name:   "Success Case - Token Renewed",
+		config: wspkg.AuthConfig{CheckInterval: 50 * time.Millisecond},
+		expire: 2 * time.Second,
+		scenario: func(t *testing.T, tester *tester_Authenticate, client *wstest.Client) {
+			client.Send(wspkg.PredefinedActionAuthenticate, wspkg.AuthenticatePayload{AccessToken: tester.token(t, "1", time.Hour)})
+			frame := client.Await(wspkg.PredefinedActionAuthenticate)
+			payload, _ := frame.Payload.(map[string]any)
+			if expiresIn, ok := payload["expires_in_ms"].(float64); !ok || expiresIn < float64(time.Minute.Milliseconds()) {
+				t.Fatalf("expected renewed expiry, got %v", frame.Payload)
+			}
+			client.ExpectSilence(2500 * time.Millisecond)
+			client.Close()
+		},
+	},
+	{
+		name:   "Failure Case - Token Expired",
+		config: wspkg.AuthConfig{CheckInterval: 50 * time.Millisecond},
+		expire: 2 * time.Second,
+		scenario: func(t *testing.T, tester *tester_Authenticate, client *wstest.Client) {
+			client.ExpectClose(wspkg.CloseCodeTokenExpired, 3*time.Second)
+			tester.expectClosed(t, wspkg.CloseCodeTokenExpired)
+		},
+	},
+	{
+		name:   "Success Case - Expiring Warning",
+		config: wspkg.AuthConfig{CheckInterval: 50 * time.Millisecond, WarnBefore: time.Hour},
+		expire: time.Minute,
+		scenario: func(t *testing.T, tester *tester_Authenticate, client *wstest.Client) {
+			client.Await(wspkg.PredefinedActionAuthExpiring)
+			client.Close()
+		},
+	},
+	{
+		name:   "Failure Case - Token Of Another User",
+		config: wspkg.AuthConfig{CheckInterval: 50 * time.Millisecond},
+		expire: time.Minute,
+		scenario: func(t *testing.T, tester *tester_Authenticate, client *wstest.Client) {
+			client.Send(wspkg.PredefinedActionAuthenticate, wspkg.AuthenticatePayload{AccessToken: tester.token(t, "2", time.Hour)})
+			frame := client.Await(wspkg.PredefinedActionReject)
+			payload, ok := frame.Payload.(map[string]any)
+			if !ok || payload["reason"] != wspkg.RejectReasonUnauthorized {
+				t.Fatalf("expected %s rejection, got %v", wspkg.RejectReasonUnauthorized, frame.Payload)
+			}
+			client.Close()
+		},
+	},
+	{
+		name:   "Failure Case - Refresh Token",
+		config: wspkg.AuthConfig{CheckInterval: 50 * time.Millisecond},
+		expire: time.Minute,
+		scenario: func(t *testing.T, tester *tester_Authenticate, client *wstest.Client) {
+			refreshToken, err := tester.generator.GenerateToken(jwt.RefreshTokenType, "1", nil, time.Hour)
+			if err != nil {
+				t.Fatalf("failed to generate token: %v", err)
+			}
+			client.Send(wspkg.PredefinedActionAuthenticate, wspkg.AuthenticatePayload{AccessToken: refreshToken})
+			client.Await(wspkg.PredefinedActionReject)
+			client.Close()
+		},
+	},
+}
+
+func Test_Authenticate(t *testing.T) {
+	for _, testcase := range testcases_Authenticate {
+		t.Run(testcase.name, func(t *testing.T) {
+			tester := &tester_Authenticate{
+				tester_Handler: prepareHandlerForTest(wspkg.Config{Auth: testcase.config}),
+				generator:      jwt.NewGenerator(jwt.Config{SecretKey: "wstest"}, clock.New()),
+			}
+			server := wstest.NewServer(t, tester, wstest.WithHub(tester.hub), wstest.WithGenerator(tester.generator))
+			client := server.Dial(t, wstest.WithAccessToken(tester.token(t, "1", testcase.expire)))
+			client.AwaitPayload(wspkg.PredefinedActionNotify, testPayloadConnected)
+			testcase.scenario(t, tester, client)
+		})
+	}
+}
+
+type tester_Authenticate struct {
+	*tester_Handler
+	generator jwt.Generator
+}
+
+func (tester *tester_Authenticate) token(t *testing.T, userID string, expire time.Duration) string {
+	t.Helper()
+	token, err := tester.generator.GenerateToken(jwt.AccessTokenType, userID, nil, expire)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	return token
+}
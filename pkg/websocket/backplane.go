@@ -0,0 +1,16 @@
+package websocket
+
+import "context"
+
+type Envelope struct {
+	ID        string          `json:"id"`
+	UserID    int64           `json:"user_id,omitempty"`
+	SessionID string          `json:"session_id,omitempty"`
+	Response  ResponseWrapper `json:"response"`
+}
+
+type Backplane interface {
+	Publish(ctx context.Context, envelope Envelope) error
+	Subscribe(ctx context.Context, deliver func(envelope Envelope)) error
+	Close() error
+}
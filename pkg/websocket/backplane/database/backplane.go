@@ -0,0 +1,188 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	"github.com/solutionchallenge/ondaum-server/pkg/websocket"
+	"github.com/uptrace/bun"
+)
+
+const (
+	Kind = "database"
+)
+
+const (
+	DefaultPollInterval = 1 * time.Second
+	DefaultRetention    = 10 * time.Minute
+	DefaultGapTimeout   = 30 * time.Second
+)
+
+var _ websocket.Backplane = &Backplane{}
+
+type Backplane struct {
+	DB            *bun.DB
+	Clock         clock.Clock
+	Config        websocket.BackplaneConfig
+	WaitGroup     sync.WaitGroup
+	CancelableCtx context.Context
+	CancelFunc    context.CancelFunc
+}
+
+func NewBackplane(db *bun.DB, clk clock.Clock, config websocket.BackplaneConfig) *Backplane {
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+	if config.Retention <= 0 {
+		config.Retention = DefaultRetention
+	}
+	if config.GapTimeout <= 0 {
+		config.GapTimeout = DefaultGapTimeout
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Backplane{
+		DB:            db,
+		Clock:         clk,
+		Config:        config,
+		CancelableCtx: ctx,
+		CancelFunc:    cancel,
+	}
+}
+
+func (b *Backplane) Publish(ctx context.Context, envelope websocket.Envelope) error {
+	marshaled, err := json.Marshal(envelope.Response)
+	if err != nil {
+		return utils.WrapError(err, "failed to marshal websocket message")
+	}
+	message := &WebsocketMessage{
+		MessageID:       envelope.ID,
+		TargetUserID:    envelope.UserID,
+		TargetSessionID: envelope.SessionID,
+		Response:        string(marshaled),
+	}
+	_, err = b.DB.NewInsert().Model(message).Value("published_at", "CURRENT_TIMESTAMP(6)").Exec(ctx)
+	if err != nil {
+		return utils.WrapError(err, "failed to publish websocket message")
+	}
+	return nil
+}
+
+func (b *Backplane) Subscribe(ctx context.Context, deliver func(envelope websocket.Envelope)) error {
+	cursor := &Cursor{Seen: make(map[int64]bool)}
+	err := b.DB.NewSelect().
+		Model((*WebsocketMessage)(nil)).
+		ColumnExpr("COALESCE(MAX(id), 0)").
+		Scan(ctx, &cursor.LastID)
+	if err != nil {
+		return utils.WrapError(err, "failed to get websocket message cursor")
+	}
+	purgedAt := b.Clock.Now().UTC()
+	b.WaitGroup.Add(1)
+	go func() {
+		defer b.WaitGroup.Done()
+		ticker := b.Clock.Ticker(b.Config.PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-b.CancelableCtx.Done():
+				return
+			case <-ticker.C:
+				b.poll(cursor, deliver)
+				if b.Clock.Since(purgedAt) >= b.Config.Retention/2 {
+					b.purge()
+					purgedAt = b.Clock.Now().UTC()
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func (b *Backplane) Close() error {
+	b.CancelFunc()
+	b.WaitGroup.Wait()
+	return nil
+}
+
+// Cursor pages through messages by id, which unlike timestamps is not subject to clock skew between pods.
+// Every id up to LastID has been delivered or given up on; Seen holds the ones delivered above it.
+type Cursor struct {
+	LastID   int64
+	Seen     map[int64]bool
+	GapSince time.Time
+}
+
+func (b *Backplane) poll(cursor *Cursor, deliver func(envelope websocket.Envelope)) {
+	messages := []WebsocketMessage{}
+	err := b.DB.NewSelect().
+		Model(&messages).
+		Where("id > ?", cursor.LastID).
+		Order("id ASC").
+		Scan(b.CancelableCtx)
+	if err != nil {
+		utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to poll websocket messages")
+		return
+	}
+	for _, message := range messages {
+		if cursor.Seen[message.ID] {
+			continue
+		}
+		cursor.Seen[message.ID] = true
+		var response websocket.ResponseWrapper
+		if err := json.Unmarshal([]byte(message.Response), &response); err != nil {
+			utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to unmarshal websocket message: %s", message.MessageID)
+			continue
+		}
+		deliver(websocket.Envelope{
+			ID:        message.MessageID,
+			UserID:    message.TargetUserID,
+			SessionID: message.TargetSessionID,
+			Response:  response,
+		})
+	}
+	cursor.advance(b.Clock.Now().UTC(), b.Config.GapTimeout)
+}
+
+func (c *Cursor) advance(now time.Time, gapTimeout time.Duration) {
+	for {
+		for c.Seen[c.LastID+1] {
+			c.LastID++
+			delete(c.Seen, c.LastID)
+			c.GapSince = time.Time{}
+		}
+		if len(c.Seen) == 0 {
+			c.GapSince = time.Time{}
+			return
+		}
+		// A missing id below a delivered one is either a slow commit, which will show up on a later poll,
+		// or a rolled back insert, which never will; the latter is given up on after the gap timeout.
+		if c.GapSince.IsZero() {
+			c.GapSince = now
+		}
+		if now.Sub(c.GapSince) < gapTimeout {
+			return
+		}
+		lowest := int64(0)
+		for ID := range c.Seen {
+			if lowest == 0 || ID < lowest {
+				lowest = ID
+			}
+		}
+		c.LastID = lowest - 1
+		c.GapSince = time.Time{}
+	}
+}
+
+func (b *Backplane) purge() {
+	_, err := b.DB.NewDelete().
+		Model((*WebsocketMessage)(nil)).
+		Where("published_at < DATE_SUB(CURRENT_TIMESTAMP(6), INTERVAL ? SECOND)", int64(b.Config.Retention.Seconds())).
+		Exec(b.CancelableCtx)
+	if err != nil {
+		utils.Log(utils.WarnLevel).Err(err).BT().Send("Failed to purge websocket messages")
+	}
+}
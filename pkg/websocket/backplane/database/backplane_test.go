@@ -0,0 +1,178 @@
+package database
+
+import (
+	"context"
+	"maps"
+	"strconv"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/benbjohnson/clock"
+	"github.com/solutionchallenge/ondaum-server/pkg/websocket"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/mysqldialect"
+)
+
+var testcases_Cursor_Advance = []struct {
+	name     string
+	cursor   Cursor
+	elapsed  time.Duration
+	expected int64
+	seen     int
+}{
+	{
+		name:     "Success Case - Contiguous Ids",
+		cursor:   Cursor{LastID: 1, Seen: map[int64]bool{2: true, 3: true}},
+		expected: 3,
+	},
+	{
+		name:     "Success Case - Gap Within Timeout",
+		cursor:   Cursor{LastID: 1, Seen: map[int64]bool{3: true, 4: true}},
+		elapsed:  29 * time.Second,
+		expected: 1,
+		seen:     2,
+	},
+	{
+		name:     "Success Case - Gap After Timeout",
+		cursor:   Cursor{LastID: 1, Seen: map[int64]bool{3: true, 4: true}},
+		elapsed:  30 * time.Second,
+		expected: 4,
+	},
+	{
+		name:     "Success Case - Next Gap Restarts Timeout",
+		cursor:   Cursor{LastID: 1, Seen: map[int64]bool{3: true, 4: true, 7: true}},
+		elapsed:  30 * time.Second,
+		expected: 4,
+		seen:     1,
+	},
+}
+
+func Test_Cursor_Advance(t *testing.T) {
+	for _, testcase := range testcases_Cursor_Advance {
+		t.Run(testcase.name, func(t *testing.T) {
+			now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			cursor := Cursor{LastID: testcase.cursor.LastID, Seen: maps.Clone(testcase.cursor.Seen)}
+			cursor.advance(now, DefaultGapTimeout)
+			cursor.advance(now.Add(testcase.elapsed), DefaultGapTimeout)
+			if cursor.LastID != testcase.expected || len(cursor.Seen) != testcase.seen {
+				t.Fatalf("expected cursor at %d with %d seen, got %d with %v", testcase.expected, testcase.seen, cursor.LastID, cursor.Seen)
+			}
+		})
+	}
+}
+
+func Test_Backplane_Poll(t *testing.T) {
+	tester, err := prepareBackplaneForTest(t)
+	if err != nil {
+		t.Fatalf("failed to prepare tester: %v", err)
+	}
+	cursor := &Cursor{Seen: make(map[int64]bool)}
+
+	tester.expectMessages(1, 3)
+	tester.backplane.poll(cursor, tester.deliver)
+	tester.expectDelivered(t, "message-1", "message-3")
+	if cursor.LastID != 1 || !cursor.Seen[3] {
+		t.Fatalf("expected cursor to wait at 1 for the gap, got %+v", cursor)
+	}
+
+	tester.expectMessages(3)
+	tester.backplane.poll(cursor, tester.deliver)
+	tester.expectDelivered(t)
+
+	tester.mockedClock.Add(DefaultGapTimeout)
+	tester.expectMessages(3, 4)
+	tester.backplane.poll(cursor, tester.deliver)
+	tester.expectDelivered(t, "message-4")
+	if cursor.LastID != 4 || len(cursor.Seen) != 0 {
+		t.Fatalf("expected cursor to give up on the gap, got %+v", cursor)
+	}
+
+	if err := tester.databaseController.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet database expectations: %v", err)
+	}
+}
+
+func Test_Backplane_Publish(t *testing.T) {
+	tester, err := prepareBackplaneForTest(t)
+	if err != nil {
+		t.Fatalf("failed to prepare tester: %v", err)
+	}
+	tester.databaseController.
+		ExpectExec("INSERT INTO `websocket_messages` .*CURRENT_TIMESTAMP\\(6\\)").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = tester.backplane.Publish(context.Background(), websocket.Envelope{
+		ID:        "message-1",
+		SessionID: "session-1",
+		Response:  websocket.BuildPushResponse("session-1", websocket.PredefinedActionNotify, "hello"),
+	})
+	if err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+	if err := tester.databaseController.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet database expectations: %v", err)
+	}
+}
+
+type tester_Backplane struct {
+	databaseController sqlmock.Sqlmock
+	mockedClock        *clock.Mock
+	backplane          *Backplane
+	delivered          []string
+}
+
+func (tester *tester_Backplane) deliver(envelope websocket.Envelope) {
+	tester.delivered = append(tester.delivered, envelope.ID)
+}
+
+func (tester *tester_Backplane) expectMessages(IDs ...int64) {
+	rows := sqlmock.NewRows([]string{"id", "message_id", "target_user_id", "target_session_id", "response", "published_at"})
+	for _, ID := range IDs {
+		rows.AddRow(ID, messageIDOf(ID), 1, "", `{"action":"notify","payload":"hello"}`, tester.mockedClock.Now())
+	}
+	tester.databaseController.
+		ExpectQuery("SELECT .* FROM `websocket_messages`").
+		WillReturnRows(rows)
+}
+
+func (tester *tester_Backplane) expectDelivered(t *testing.T, messageIDs ...string) {
+	t.Helper()
+	if len(tester.delivered) != len(messageIDs) {
+		t.Fatalf("expected %v delivered, got %v", messageIDs, tester.delivered)
+	}
+	for i, messageID := range messageIDs {
+		if tester.delivered[i] != messageID {
+			t.Fatalf("expected %v delivered, got %v", messageIDs, tester.delivered)
+		}
+	}
+	tester.delivered = nil
+}
+
+func messageIDOf(ID int64) string {
+	return "message-" + strconv.FormatInt(ID, 10)
+}
+
+func prepareBackplaneForTest(t *testing.T) (*tester_Backplane, error) {
+	mockedDatabase, databaseController, err := sqlmock.New()
+	if err != nil {
+		return nil, err
+	}
+	databaseController.
+		ExpectQuery("SELECT version()").
+		WithoutArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"version()"}).AddRow("8.0.28"))
+	mockedORM := bun.NewDB(mockedDatabase, mysqldialect.New())
+	t.Cleanup(func() {
+		mockedORM.Close()
+	})
+
+	mockedClock := clock.NewMock()
+	backplane := NewBackplane(mockedORM, mockedClock, websocket.BackplaneConfig{})
+
+	return &tester_Backplane{
+		databaseController: databaseController,
+		mockedClock:        mockedClock,
+		backplane:          backplane,
+	}, nil
+}
@@ -0,0 +1,29 @@
+package database
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type WebsocketMessage struct {
+	bun.BaseModel   `bun:"websocket_messages"`
+	ID              int64     `bun:"id,pk,autoincrement"`
+	MessageID       string    `bun:"message_id,notnull"`
+	TargetUserID    int64     `bun:"target_user_id"`
+	TargetSessionID string    `bun:"target_session_id"`
+	Response        string    `bun:"response,notnull"`
+	PublishedAt     time.Time `bun:"published_at,notnull"`
+}
+
+const WebsocketMessageTableCreationSQL = `
+CREATE TABLE IF NOT EXISTS websocket_messages (
+	id BIGINT PRIMARY KEY AUTO_INCREMENT,
+	message_id VARCHAR(255) NOT NULL,
+	target_user_id BIGINT,
+	target_session_id VARCHAR(255),
+	response TEXT NOT NULL,
+	published_at DATETIME(6) NOT NULL,
+	INDEX idx_published_at (published_at)
+)
+`
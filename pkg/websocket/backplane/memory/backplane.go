@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/solutionchallenge/ondaum-server/pkg/websocket"
+)
+
+const (
+	Kind = "memory"
+)
+
+var _ websocket.Backplane = &Backplane{}
+
+type Backplane struct {
+	Subscribers []func(envelope websocket.Envelope)
+	Mutex       sync.RWMutex
+}
+
+func NewBackplane() *Backplane {
+	return &Backplane{}
+}
+
+func (b *Backplane) Publish(_ context.Context, envelope websocket.Envelope) error {
+	b.Mutex.RLock()
+	subscribers := make([]func(envelope websocket.Envelope), len(b.Subscribers))
+	copy(subscribers, b.Subscribers)
+	b.Mutex.RUnlock()
+	for _, deliver := range subscribers {
+		deliver(envelope)
+	}
+	return nil
+}
+
+func (b *Backplane) Subscribe(_ context.Context, deliver func(envelope websocket.Envelope)) error {
+	b.Mutex.Lock()
+	defer b.Mutex.Unlock()
+	b.Subscribers = append(b.Subscribers, deliver)
+	return nil
+}
+
+func (b *Backplane) Close() error {
+	b.Mutex.Lock()
+	defer b.Mutex.Unlock()
+	b.Subscribers = nil
+	return nil
+}
@@ -0,0 +1,95 @@
+package websocket
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	fiberws "github.com/gofiber/websocket/v2"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+const (
+	ProtocolJSON    = "ondaum.json.v1"
+	ProtocolMsgpack = "ondaum.msgpack.v1"
+)
+
+var SupportedProtocols = []string{
+	ProtocolJSON,
+	ProtocolMsgpack,
+}
+
+type Codec interface {
+	Protocol() string
+	MessageType() int
+	Marshal(value any) ([]byte, error)
+	Unmarshal(data []byte, value any) error
+}
+
+type JSONCodec struct{}
+
+func (JSONCodec) Protocol() string {
+	return ProtocolJSON
+}
+
+func (JSONCodec) MessageType() int {
+	return fiberws.TextMessage
+}
+
+func (JSONCodec) Marshal(value any) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (JSONCodec) Unmarshal(data []byte, value any) error {
+	return json.Unmarshal(data, value)
+}
+
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) Protocol() string {
+	return ProtocolMsgpack
+}
+
+func (MsgpackCodec) MessageType() int {
+	return fiberws.BinaryMessage
+}
+
+func (MsgpackCodec) Marshal(value any) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := msgpack.NewEncoder(&buffer)
+	encoder.SetCustomStructTag("json")
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (MsgpackCodec) Unmarshal(data []byte, value any) error {
+	decoder := msgpack.NewDecoder(bytes.NewReader(data))
+	decoder.SetCustomStructTag("json")
+	return decoder.Decode(value)
+}
+
+func NewCodec(protocol string) (Codec, error) {
+	switch protocol {
+	case "", ProtocolJSON:
+		return JSONCodec{}, nil
+	case ProtocolMsgpack:
+		return MsgpackCodec{}, nil
+	default:
+		return nil, utils.NewError("unsupported protocol: %s", protocol)
+	}
+}
+
+func NegotiateProtocol(header string) string {
+	for _, offered := range strings.Split(header, ",") {
+		offered = strings.TrimSpace(offered)
+		for _, supported := range SupportedProtocols {
+			if offered == supported {
+				return supported
+			}
+		}
+	}
+	return ""
+}
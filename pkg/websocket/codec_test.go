@@ -0,0 +1,121 @@
+package websocket
+
+import (
+	"testing"
+
+	fiberws "github.com/gofiber/websocket/v2"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var testcases_NegotiateProtocol = []struct {
+	name     string
+	header   string
+	expected string
+}{
+	{
+		name:     "Success Case - Single Protocol",
+		header:   ProtocolMsgpack,
+		expected: ProtocolMsgpack,
+	},
+	{
+		name:     "Success Case - First Supported Wins",
+		header:   "ondaum.cbor.v1, " + ProtocolJSON + ", " + ProtocolMsgpack,
+		expected: ProtocolJSON,
+	},
+	{
+		name:     "Failure Case - Unsupported Protocol",
+		header:   "ondaum.cbor.v1",
+		expected: "",
+	},
+	{
+		name:     "Failure Case - Empty Header",
+		header:   "",
+		expected: "",
+	},
+}
+
+func Test_NegotiateProtocol(t *testing.T) {
+	for _, testcase := range testcases_NegotiateProtocol {
+		t.Run(testcase.name, func(t *testing.T) {
+			if protocol := NegotiateProtocol(testcase.header); protocol != testcase.expected {
+				t.Fatalf("expected %q, got %q", testcase.expected, protocol)
+			}
+		})
+	}
+}
+
+var testcases_Codec = []struct {
+	name        string
+	protocol    string
+	messageType int
+}{
+	{
+		name:        "Success Case - Default JSON",
+		protocol:    "",
+		messageType: fiberws.TextMessage,
+	},
+	{
+		name:        "Success Case - JSON",
+		protocol:    ProtocolJSON,
+		messageType: fiberws.TextMessage,
+	},
+	{
+		name:        "Success Case - MessagePack",
+		protocol:    ProtocolMsgpack,
+		messageType: fiberws.BinaryMessage,
+	},
+}
+
+func Test_Codec(t *testing.T) {
+	for _, testcase := range testcases_Codec {
+		t.Run(testcase.name, func(t *testing.T) {
+			codec, err := NewCodec(testcase.protocol)
+			if err != nil {
+				t.Fatalf("failed to create codec: %v", err)
+			}
+			if codec.MessageType() != testcase.messageType {
+				t.Fatalf("expected message type %d, got %d", testcase.messageType, codec.MessageType())
+			}
+
+			sent := ResponseWrapper{
+				Action:    PredefinedActionData,
+				Payload:   map[string]any{"text": "hello"},
+				SessionID: "session",
+				MessageID: "message",
+				Sequence:  7,
+			}
+			serialized, err := codec.Marshal(sent)
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+			var received ResponseWrapper
+			if err := codec.Unmarshal(serialized, &received); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+			payload, ok := received.Payload.(map[string]any)
+			if received.Action != sent.Action || received.SessionID != sent.SessionID || received.Sequence != sent.Sequence || !ok || payload["text"] != "hello" {
+				t.Fatalf("expected %+v, got %+v", sent, received)
+			}
+		})
+	}
+}
+
+func Test_MsgpackCodec_Keys(t *testing.T) {
+	serialized, err := MsgpackCodec{}.Marshal(InboundFrame{Action: "chat", Payload: "hello"})
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	var decoded map[string]any
+	if err := msgpack.Unmarshal(serialized, &decoded); err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	if decoded["action"] != "chat" || decoded["payload"] != "hello" {
+		t.Fatalf("expected json field names, got %v", decoded)
+	}
+}
+
+func Test_NewCodec_Unsupported(t *testing.T) {
+	if _, err := NewCodec("ondaum.cbor.v1"); err == nil {
+		t.Fatalf("expected unsupported protocol to fail")
+	}
+}
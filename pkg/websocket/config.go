@@ -0,0 +1,60 @@
+package websocket
+
+import "time"
+
+type Config struct {
+	Backplane BackplaneConfig `mapstructure:"backplane"`
+	Heartbeat HeartbeatConfig `mapstructure:"heartbeat"`
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	Worker    WorkerConfig    `mapstructure:"worker"`
+	Drain     DrainConfig     `mapstructure:"drain"`
+	Auth      AuthConfig      `mapstructure:"auth"`
+}
+
+type BackplaneConfig struct {
+	Kind         string        `mapstructure:"kind" default:"memory"`
+	PollInterval time.Duration `mapstructure:"poll_interval" default:"1s"`
+	Retention    time.Duration `mapstructure:"retention" default:"10m"`
+	DedupWindow  time.Duration `mapstructure:"dedup_window" default:"1m"`
+	GapTimeout   time.Duration `mapstructure:"gap_timeout" default:"30s"`
+}
+
+type HeartbeatConfig struct {
+	PingInterval time.Duration `mapstructure:"ping_interval" default:"30s"`
+	PongTimeout  time.Duration `mapstructure:"pong_timeout" default:"10s"`
+	MaxIdle      time.Duration `mapstructure:"max_idle" default:"30m"`
+	MaxLifetime  time.Duration `mapstructure:"max_lifetime" default:"6h"`
+}
+
+type RateLimitConfig struct {
+	MaxMessageSize int64                        `mapstructure:"max_message_size" default:"65536"`
+	AbuseThreshold int                          `mapstructure:"abuse_threshold" default:"10"`
+	IdleTTL        time.Duration                `mapstructure:"idle_ttl" default:"10m"`
+	Actions        map[string]ActionLimitConfig `mapstructure:"actions"`
+}
+
+type ActionLimitConfig struct {
+	Connection BucketConfig `mapstructure:"connection"`
+	User       BucketConfig `mapstructure:"user"`
+}
+
+type BucketConfig struct {
+	Rate  float64 `mapstructure:"rate"`
+	Burst int     `mapstructure:"burst"`
+}
+
+type WorkerConfig struct {
+	QueueSize  int `mapstructure:"queue_size" default:"16"`
+	OutboxSize int `mapstructure:"outbox_size" default:"64"`
+}
+
+type DrainConfig struct {
+	GracePeriod    time.Duration `mapstructure:"grace_period" default:"10s"`
+	ReconnectAfter time.Duration `mapstructure:"reconnect_after" default:"3s"`
+	Timeout        time.Duration `mapstructure:"timeout" default:"30s"`
+}
+
+type AuthConfig struct {
+	WarnBefore    time.Duration `mapstructure:"warn_before" default:"1m"`
+	CheckInterval time.Duration `mapstructure:"check_interval" default:"5s"`
+}
@@ -24,6 +24,15 @@ func EnableWebsocketCore(app fiber.Router, path string, generator jwt.Generator)
 		}
 		c.Locals("X-Websocket-Session-ID", sid)
 
+		if offered := c.Get(fiber.HeaderSecWebSocketProtocol); offered != "" {
+			if protocol := NegotiateProtocol(offered); protocol != "" {
+				c.Request().Header.Set(fiber.HeaderSecWebSocketProtocol, protocol)
+				utils.Log(utils.DebugLevel).Ctx(c.UserContext()).RID(sid).BT().Send("Negotiated protocol %s from %s", protocol, offered)
+			} else {
+				utils.Log(utils.WarnLevel).Ctx(c.UserContext()).RID(sid).BT().Send("No supported protocol in %s", offered)
+			}
+		}
+
 		token := c.Query("access_token")
 		if token == "" {
 			tryWebsocketAuthorization(c, generator, sid)
@@ -73,4 +82,7 @@ func tryWebsocketAuthorization(c *fiber.Ctx, generator jwt.Generator, sessionID
 	utils.Log(utils.InfoLevel).Ctx(c.UserContext()).RID(sessionID).BT().Send("Unpacked User: %v (%+v)", claims.Value, claims.Metadata)
 	c.Locals("X-Websocket-User-Metadata", claims.Metadata)
 	c.Locals("X-Websocket-User-ID", claims.Value)
+	if claims.ExpiresAt != nil {
+		c.Locals("X-Websocket-Token-Expiry", claims.ExpiresAt.Time)
+	}
 }
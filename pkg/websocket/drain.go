@@ -0,0 +1,126 @@
+package websocket
+
+import (
+	"context"
+	"time"
+
+	fiberws "github.com/gofiber/websocket/v2"
+	"github.com/google/uuid"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+)
+
+const (
+	NotifyPayloadServerRestarting = "server_restarting"
+)
+
+const (
+	DefaultDrainPollInterval = 50 * time.Millisecond
+	DefaultDrainTimeout      = 30 * time.Second
+)
+
+func (h *Hub) Draining() bool {
+	return h.DrainState.Load()
+}
+
+func (h *Hub) Drain(ctx context.Context) error {
+	if !h.DrainState.CompareAndSwap(false, true) {
+		return nil
+	}
+	timeout := h.Config.Drain.Timeout
+	if timeout <= 0 {
+		timeout = DefaultDrainTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	connections := h.snapshot()
+	utils.Log(utils.InfoLevel).BT().Send("Draining %d websocket connections", len(connections))
+
+	deliverResponse(connections, BuildRestartResponse(h.Config.Drain.ReconnectAfter))
+
+	if err := h.awaitInFlight(ctx, h.Config.Drain.GracePeriod); err != nil {
+		return h.forceClose(err)
+	}
+
+	ticker := time.NewTicker(DefaultDrainPollInterval)
+	defer ticker.Stop()
+	for {
+		for _, conn := range h.snapshot() {
+			if _, terminated := conn.Terminated(); !terminated {
+				conn.Schedule(func() {
+					conn.Terminate(conn.SessionID, "", "server restarting", fiberws.CloseServiceRestart)
+				})
+			}
+		}
+		if h.OpenConnections.Load() <= 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return h.forceClose(ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *Hub) forceClose(cause error) error {
+	connections := h.snapshot()
+	for _, conn := range connections {
+		// The connection worker may be stuck in a handler, so the socket is closed from here to unblock its read loop.
+		conn.Close()
+		if conn.Conn != nil {
+			conn.Conn.Close()
+		}
+	}
+	return utils.WrapError(cause, "force closed %d websocket connections", len(connections))
+}
+
+func BuildRestartResponse(reconnectAfter time.Duration) ResponseWrapper {
+	return ResponseWrapper{
+		Action:           PredefinedActionNotify,
+		Payload:          NotifyPayloadServerRestarting,
+		MessageID:        uuid.New().String(),
+		ReconnectAfterMS: reconnectAfter.Milliseconds(),
+	}
+}
+
+func BuildRestartingResponse(request MessageWrapper, reconnectAfter time.Duration) ResponseWrapper {
+	return BuildResponseFrom(request,
+		uuid.New().String(),
+		PredefinedActionReject, RejectPayload{
+			Reason:       RejectReasonRestarting,
+			Action:       request.Action,
+			RetryAfterMS: reconnectAfter.Milliseconds(),
+		},
+	)
+}
+
+func (h *Hub) snapshot() []*Connection {
+	h.Mutex.RLock()
+	defer h.Mutex.RUnlock()
+	connections := []*Connection{}
+	for _, session := range h.Sessions {
+		connections = append(connections, collectConnections(session)...)
+	}
+	return connections
+}
+
+func (h *Hub) awaitInFlight(ctx context.Context, grace time.Duration) error {
+	ticker := time.NewTicker(DefaultDrainPollInterval)
+	defer ticker.Stop()
+	deadline := time.After(grace)
+	for {
+		inFlight := h.InFlightMessages.Load()
+		if inFlight <= 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return utils.WrapError(ctx.Err(), "failed to wait for %d in-flight messages", inFlight)
+		case <-deadline:
+			utils.Log(utils.WarnLevel).BT().Send("Grace period elapsed with %d in-flight messages", inFlight)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
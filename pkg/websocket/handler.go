@@ -1,14 +1,17 @@
 package websocket
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"slices"
 	"time"
 
+	fastws "github.com/fasthttp/websocket"
 	"github.com/gofiber/fiber/v2"
 	fiberws "github.com/gofiber/websocket/v2"
 	"github.com/google/uuid"
+	"github.com/solutionchallenge/ondaum-server/pkg/jwt"
 	"github.com/solutionchallenge/ondaum-server/pkg/utils"
 )
 
@@ -20,98 +23,228 @@ type Handler interface {
 	HandleClose(c *fiberws.Conn, payload CloseWrapper)
 }
 
-func Install(router fiber.Router, path string, handler Handler) fiber.Router {
-	return router.Get(path, fiberws.New(func(c *fiberws.Conn) {
-		responseWrapper, sessionID, err := handleConnect(c, handler)
+type ImmediateActionClassifier interface {
+	IsImmediateAction(action Action) bool
+}
+
+func Install(router fiber.Router, path string, handler Handler, hub *Hub, generator jwt.Generator) fiber.Router {
+	return router.Get(path, func(c *fiber.Ctx) error {
+		if hub.Draining() {
+			return c.SendStatus(fiber.StatusServiceUnavailable)
+		}
+		return c.Next()
+	}, fiberws.New(func(c *fiberws.Conn) {
+		conn := NewConnection(c, hub.Config.Worker.OutboxSize)
+		codec, err := NewCodec(c.Subprotocol())
+		if err != nil {
+			utils.Log(utils.WarnLevel).Err(err).BT().Send("Falling back to default protocol")
+			codec = JSONCodec{}
+		}
+		conn.Codec = codec
+		stopWriter := conn.StartWriter()
+		defer stopWriter()
+		hub.OpenConnections.Add(1)
+		defer hub.OpenConnections.Add(-1)
+		responseWrapper, sessionID, err := handleConnect(c, codec, handler)
 		if err != nil {
 			utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).BT().Send("Failed to connect")
-			closeConnection(c, sessionID, "", "failed to connect", fiberws.CloseProtocolError)
+			closeConnection(conn, sessionID, "", "failed to connect", fiberws.CloseProtocolError)
 			return
 		}
-		closed, err := processControlFlags(c, responseWrapper)
+		responseWrapper.Protocol = codec.Protocol()
+		closed, err := processControlFlags(conn, responseWrapper)
 		if err != nil {
 			utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).BT().Send("Failed to process control flags")
-			closeConnection(c, sessionID, "", "failed to process control flags", fiberws.CloseProtocolError)
+			closeConnection(conn, sessionID, "", "failed to process control flags", fiberws.CloseProtocolError)
 			return
 		} else if closed {
 			utils.Log(utils.InfoLevel).CID(sessionID).BT().Send("Closing connection")
-			closeConnection(c, sessionID, "", "server requested")
+			closeConnection(conn, sessionID, "", "server requested")
+			return
+		}
+		if hub.Draining() {
+			utils.Log(utils.InfoLevel).CID(sessionID).BT().Send("Refusing connection while draining")
+			closeConnection(conn, sessionID, "", "server restarting", fiberws.CloseServiceRestart)
 			return
 		}
-		for {
-			messageID := uuid.New().String()
-			messageType, rawMessage, err := c.ReadMessage()
+		conn.SessionID = sessionID
+		if userID, err := GetWebsocketUserID(c); err == nil {
+			conn.UserID = userID
+		}
+		if expiry, err := GetWebsocketTokenExpiry(c); err == nil {
+			conn.SetTokenExpiry(expiry)
+		}
+		hub.Register(conn)
+		defer hub.Unregister(conn)
+		worker := hub.AcquireWorker(sessionID)
+		defer hub.ReleaseWorker(worker)
+		stopHeartbeat := startHeartbeat(conn, hub.Config.Heartbeat, sessionID)
+		defer stopHeartbeat()
+		stopAuthWatch := startAuthWatch(conn, hub.Config.Auth, sessionID)
+		defer stopAuthWatch()
+		if hub.Config.RateLimit.MaxMessageSize > 0 {
+			c.SetReadLimit(hub.Config.RateLimit.MaxMessageSize)
+		}
+		closeCode := serveMessages(c, conn, hub, worker, handler, generator)
+		conn.Pending.Wait()
+		// Unregister before the close handler so it sees only the session's other connections.
+		hub.Unregister(conn)
+		handleClose(c, sessionID, closeCode, handler)
+	}, fiberws.Config{Subprotocols: SupportedProtocols})).Name(handler.Identify())
+}
+
+func serveMessages(c *fiberws.Conn, conn *Connection, hub *Hub, worker *SessionWorker, handler Handler, generator jwt.Generator) int {
+	sessionID := conn.SessionID
+	for {
+		messageID := uuid.New().String()
+		messageType, rawMessage, err := c.ReadMessage()
+		if err != nil {
+			if code, reaped := conn.Reaped(); reaped {
+				utils.Log(utils.InfoLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Connection reaped with code %d", code)
+				hub.ReapedConnections.Add(1)
+				return code
+			}
+			if code, terminated := conn.Terminated(); terminated {
+				utils.Log(utils.InfoLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Connection terminated with code %d", code)
+				return code
+			}
+			var netErr net.Error
+			switch {
+			case errors.Is(err, fastws.ErrReadLimit):
+				utils.Log(utils.WarnLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Message exceeded size limit")
+				return fiberws.CloseMessageTooBig
+			case errors.As(err, &netErr) && netErr.Timeout():
+				utils.Log(utils.InfoLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Connection timed out waiting for pong")
+				conn.Reap(sessionID, CloseCodePongTimeout, "pong timeout")
+				hub.ReapedConnections.Add(1)
+				return CloseCodePongTimeout
+			case fiberws.IsCloseError(err, fiberws.CloseNormalClosure, fiberws.CloseGoingAway, fiberws.CloseAbnormalClosure):
+				utils.Log(utils.InfoLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Connection closed by client")
+				return fiberws.CloseNormalClosure
+			default:
+				utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Failed to read message")
+				return fiberws.CloseInternalServerErr
+			}
+		}
+		switch messageType {
+		case fiberws.TextMessage, fiberws.BinaryMessage:
+			conn.Touch()
+			requestWrapper, err := decodeMessage(c, conn.Codec, sessionID, messageID, rawMessage)
 			if err != nil {
-				switch {
-				case fiberws.IsCloseError(err, fiberws.CloseNormalClosure, fiberws.CloseGoingAway, fiberws.CloseAbnormalClosure):
-					utils.Log(utils.InfoLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Connection closed by client")
-					handleClose(c, sessionID, fiberws.CloseNormalClosure, handler)
-					return
-				default:
-					utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Failed to read message")
-					handleClose(c, sessionID, fiberws.CloseInternalServerErr, handler)
-					return
-				}
+				utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Failed to decode payload message")
+				continue
+			}
+			if conn.TokenExpired(time.Now()) {
+				utils.Log(utils.InfoLevel).CID(sessionID).RID(messageID).BT().Send("Reaping connection with expired token")
+				conn.Reap(sessionID, CloseCodeTokenExpired, "token expired")
+				continue
 			}
-			switch messageType {
-			case fiberws.TextMessage, fiberws.BinaryMessage:
-				responseWrapper, isCritical, err := handleMessage(c, sessionID, messageID, rawMessage, handler)
-				if err != nil {
-					if isCritical {
-						utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Occurred critical error")
-						closeConnection(c, sessionID, messageID, "server occurred critical error", fiberws.CloseInternalServerErr)
-						handleClose(c, sessionID, fiberws.CloseInternalServerErr, handler)
-						return
-					}
-					utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Failed to handle payload message")
-					continue
+			if allowed, retryAfter := hub.Limiter.Allow(conn, requestWrapper.Action); !allowed {
+				conn.Violations++
+				if hub.Config.RateLimit.AbuseThreshold > 0 && conn.Violations >= hub.Config.RateLimit.AbuseThreshold {
+					utils.Log(utils.WarnLevel).CID(sessionID).RID(messageID).BT().Send("Closing abusive connection")
+					closeConnection(conn, sessionID, messageID, "too many requests", fiberws.ClosePolicyViolation)
+					return fiberws.ClosePolicyViolation
 				}
-				closed, err := processControlFlags(c, responseWrapper)
-				if err != nil {
+				utils.Log(utils.InfoLevel).CID(sessionID).RID(messageID).BT().Send("Throttled action %v (retry after %v)", requestWrapper.Action, retryAfter)
+				if _, err := processControlFlags(conn, BuildThrottleResponse(requestWrapper, retryAfter)); err != nil {
 					utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Failed to process control flags")
-					continue
-				} else if closed {
-					utils.Log(utils.InfoLevel).CID(sessionID).RID(messageID).BT().Send("Closing connection")
-					closeConnection(c, sessionID, messageID, "server requested")
-					return
 				}
-			case fiberws.PingMessage:
-				responseWrapper, isCritical, err := handlePing(c, sessionID, messageID, handler)
-				if err != nil {
-					if isCritical {
-						utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Occurred critical error in ping")
-						closeConnection(c, sessionID, messageID, "server occurred critical error", fiberws.CloseInternalServerErr)
-						handleClose(c, sessionID, fiberws.CloseInternalServerErr, handler)
-						return
-					}
-					utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Failed to handle ping")
-					continue
+				continue
+			}
+			conn.Violations = 0
+			if requestWrapper.Action == PredefinedActionAuthenticate {
+				if _, err := processControlFlags(conn, handleAuthenticate(conn, generator, requestWrapper)); err != nil {
+					utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Failed to process control flags")
 				}
-				closed, err := processControlFlags(c, responseWrapper)
-				if err != nil {
+				continue
+			}
+			if classifier, ok := handler.(ImmediateActionClassifier); ok && classifier.IsImmediateAction(requestWrapper.Action) {
+				dispatchMessage(c, conn, requestWrapper, handler)
+				continue
+			}
+			if hub.Draining() {
+				utils.Log(utils.InfoLevel).CID(sessionID).RID(messageID).BT().Send("Refusing %v while draining", requestWrapper.Action)
+				if _, err := processControlFlags(conn, BuildRestartingResponse(requestWrapper, hub.Config.Drain.ReconnectAfter)); err != nil {
 					utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Failed to process control flags")
-					continue
-				} else if closed {
-					utils.Log(utils.InfoLevel).CID(sessionID).RID(messageID).BT().Send("Closing connection")
-					closeConnection(c, sessionID, messageID, "server requested")
-					return
 				}
-			case fiberws.PongMessage:
 				continue
-			case fiberws.CloseMessage:
-				utils.Log(utils.InfoLevel).CID(sessionID).RID(messageID).BT().Send("Connection closed")
-				handleClose(c, sessionID, fiberws.CloseNormalClosure, handler)
-				// already closed by client so no need to handle
-				return
-			default:
-				utils.Log(utils.WarnLevel).CID(sessionID).RID(messageID).BT().Send("Unknown message type")
 			}
+			conn.Pending.Add(1)
+			hub.InFlightMessages.Add(1)
+			submitted := worker.Submit(func() {
+				defer conn.Pending.Done()
+				defer hub.InFlightMessages.Add(-1)
+				dispatchMessage(c, conn, requestWrapper, handler)
+			})
+			if !submitted {
+				conn.Pending.Done()
+				hub.InFlightMessages.Add(-1)
+				utils.Log(utils.WarnLevel).CID(sessionID).RID(messageID).BT().Send("Session worker queue is full")
+				if _, err := processControlFlags(conn, BuildErrorResponse(requestWrapper, RejectReasonBusy, nil)); err != nil {
+					utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Failed to process control flags")
+				}
+			}
+		case fiberws.PingMessage:
+			responseWrapper, isCritical, err := handlePing(c, sessionID, messageID, handler)
+			if err != nil {
+				if isCritical {
+					utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Occurred critical error in ping")
+					closeConnection(conn, sessionID, messageID, "server occurred critical error", fiberws.CloseInternalServerErr)
+					return fiberws.CloseInternalServerErr
+				}
+				utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Failed to handle ping")
+				continue
+			}
+			closed, err := processControlFlags(conn, responseWrapper)
+			if err != nil {
+				utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Failed to process control flags")
+				continue
+			} else if closed {
+				utils.Log(utils.InfoLevel).CID(sessionID).RID(messageID).BT().Send("Closing connection")
+				closeConnection(conn, sessionID, messageID, "server requested")
+				return fiberws.CloseNormalClosure
+			}
+		case fiberws.PongMessage:
+			continue
+		case fiberws.CloseMessage:
+			utils.Log(utils.InfoLevel).CID(sessionID).RID(messageID).BT().Send("Connection closed")
+			// already closed by client so no need to handle
+			return fiberws.CloseNormalClosure
+		default:
+			utils.Log(utils.WarnLevel).CID(sessionID).RID(messageID).BT().Send("Unknown message type")
 		}
-	})).Name(handler.Identify())
+	}
+}
+
+func dispatchMessage(c *fiberws.Conn, conn *Connection, request MessageWrapper, handler Handler) {
+	sessionID, messageID := request.SessionID, request.MessageID
+	if _, terminated := conn.Terminated(); terminated {
+		utils.Log(utils.DebugLevel).CID(sessionID).RID(messageID).BT().Send("Skipping message for terminated connection")
+		return
+	}
+	responseWrapper, isCritical, err := handler.HandleMessage(c, request)
+	if err != nil {
+		if isCritical {
+			utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Occurred critical error")
+			conn.Terminate(sessionID, messageID, "server occurred critical error", fiberws.CloseInternalServerErr)
+			return
+		}
+		utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Failed to handle payload message")
+		return
+	}
+	closed, err := processControlFlags(conn, responseWrapper)
+	if err != nil {
+		utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Failed to process control flags")
+		return
+	} else if closed {
+		utils.Log(utils.InfoLevel).CID(sessionID).RID(messageID).BT().Send("Closing connection")
+		conn.Terminate(sessionID, messageID, "server requested", fiberws.CloseNormalClosure)
+	}
 }
 
 func handleConnect(
-	c *fiberws.Conn, handler Handler,
+	c *fiberws.Conn, codec Codec, handler Handler,
 ) (ResponseWrapper, string, error) {
 	sessionID, err := GetWebsocketSessionID(c)
 	if err != nil {
@@ -119,6 +252,7 @@ func handleConnect(
 	}
 	connectWrapper := ConnectWrapper{
 		ConnectID: sessionID,
+		Protocol:  codec.Protocol(),
 	}
 	userID, err := GetWebsocketUserID(c)
 	if err == nil {
@@ -132,16 +266,21 @@ func handleConnect(
 	return handler.HandleConnect(c, connectWrapper)
 }
 
-func handleMessage(
-	c *fiberws.Conn, sessionID string, messageID string, rawMessage []byte, handler Handler,
-) (ResponseWrapper, bool, error) {
-	var requestWrapper MessageWrapper
-	err := json.Unmarshal(rawMessage, &requestWrapper)
+func decodeMessage(
+	c *fiberws.Conn, codec Codec, sessionID string, messageID string, rawMessage []byte,
+) (MessageWrapper, error) {
+	var frame InboundFrame
+	err := codec.Unmarshal(rawMessage, &frame)
 	if err != nil {
-		return ResponseWrapper{}, false, utils.WrapError(err, "failed to unmarshal message")
+		return MessageWrapper{}, utils.WrapError(err, "failed to unmarshal message")
+	}
+	requestWrapper := MessageWrapper{
+		Action:     frame.Action,
+		Payload:    frame.Payload,
+		SessionID:  sessionID,
+		MessageID:  messageID,
+		ReceivedAt: time.Now(),
 	}
-	requestWrapper.MessageID = messageID
-	requestWrapper.SessionID = sessionID
 	userID, err := GetWebsocketUserID(c)
 	if err == nil {
 		requestWrapper.Authorized = true
@@ -151,7 +290,7 @@ func handleMessage(
 			requestWrapper.UserMetadata = userMetadata
 		}
 	}
-	return handler.HandleMessage(c, requestWrapper)
+	return requestWrapper, nil
 }
 
 func handlePing(
@@ -193,17 +332,13 @@ func handleClose(
 }
 
 func processControlFlags(
-	c *fiberws.Conn,
+	conn *Connection,
 	responseWrapper ResponseWrapper,
 ) (bool, error) {
 	if !slices.Contains(responseWrapper.ControlFlags, ControlFlagQuite) {
-		serialized, err := json.Marshal(responseWrapper)
+		err := conn.WriteResponse(responseWrapper)
 		if err != nil {
-			return false, utils.WrapError(err, "failed to serialize message")
-		}
-		err = c.WriteMessage(fiberws.TextMessage, serialized)
-		if err != nil {
-			return false, utils.WrapError(err, "failed to write message")
+			return false, err
 		}
 	}
 	if slices.Contains(responseWrapper.ControlFlags, ControlFlagClose) {
@@ -212,15 +347,19 @@ func processControlFlags(
 	return false, nil
 }
 
-func closeConnection(c *fiberws.Conn, sessionID string, messageID string, reason string, cause ...int) {
+func closeConnection(conn *Connection, sessionID string, messageID string, reason string, cause ...int) {
+	if conn.Conn == nil {
+		conn.Close()
+		return
+	}
 	code := fiberws.CloseNormalClosure
 	if len(cause) > 0 {
 		code = cause[0]
 	}
 	payload := fmt.Sprintf("connection closed by server: %s", reason)
 	message := fiberws.FormatCloseMessage(code, payload)
-	if err := c.WriteControl(fiberws.CloseMessage, message, time.Now().UTC().Add(time.Second)); err != nil {
+	if err := conn.WriteControl(fiberws.CloseMessage, message, time.Now().UTC().Add(time.Second)); err != nil {
 		utils.Log(utils.ErrorLevel).Err(err).CID(sessionID).RID(messageID).BT().Send("Failed to send close message")
 	}
-	c.Close()
+	conn.Conn.Close()
 }
@@ -0,0 +1,293 @@
+package websocket_test
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	fiberws "github.com/gofiber/websocket/v2"
+	"github.com/google/uuid"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
+	"github.com/solutionchallenge/ondaum-server/pkg/websocket/wstest"
+)
+
+const (
+	testActionEcho = wspkg.Action("echo")
+	testActionSlow = wspkg.Action("slow")
+
+	testPayloadConnected = "connected"
+)
+
+var testcases_Handler = []struct {
+	name     string
+	config   wspkg.Config
+	dial     []wstest.DialOption
+	scenario func(t *testing.T, tester *tester_Handler, client *wstest.Client)
+}{
+	{
+		name: "Success Case - Echo",
+		dial: []wstest.DialOption{wstest.WithUserID(1)},
+		scenario: func(t *testing.T, tester *tester_Handler, client *wstest.Client) {
+			client.Send(testActionEcho, "hello")
+			client.AwaitPayload(wspkg.PredefinedActionData, "hello")
+			client.Close()
+		},
+	},
+	{
+		name: "Success Case - MessagePack Echo",
+		dial: []wstest.DialOption{wstest.WithUserID(1), wstest.WithProtocol("ondaum.cbor.v1", wspkg.ProtocolMsgpack)},
+		scenario: func(t *testing.T, tester *tester_Handler, client *wstest.Client) {
+			if _, ok := client.Codec.(wspkg.MsgpackCodec); !ok {
+				t.Fatalf("expected msgpack to be negotiated, got %s", client.Codec.Protocol())
+			}
+			client.Send(testActionEcho, map[string]any{"text": "hello"})
+			client.AwaitPayload(wspkg.PredefinedActionData, map[string]any{"text": "hello"})
+			client.Close()
+		},
+	},
+	{
+		name: "Success Case - Responses In Arrival Order",
+		dial: []wstest.DialOption{wstest.WithUserID(1)},
+		scenario: func(t *testing.T, tester *tester_Handler, client *wstest.Client) {
+			client.Send(testActionSlow, "first")
+			client.Send(testActionEcho, "second")
+			if frame := client.Await(wspkg.PredefinedActionData); frame.Payload != "first" {
+				t.Fatalf("expected first response, got %v", frame.Payload)
+			}
+			if frame := client.Await(wspkg.PredefinedActionData); frame.Payload != "second" {
+				t.Fatalf("expected second response, got %v", frame.Payload)
+			}
+			client.Close()
+		},
+	},
+	{
+		name: "Success Case - Close Waits For Pending Messages",
+		dial: []wstest.DialOption{wstest.WithUserID(1)},
+		scenario: func(t *testing.T, tester *tester_Handler, client *wstest.Client) {
+			client.Send(testActionSlow, "pending")
+			client.Close()
+			tester.expectClosed(t, fiberws.CloseNormalClosure)
+			if handled := tester.handled.Load(); handled != 1 {
+				t.Fatalf("expected pending message to finish before close, got %d handled", handled)
+			}
+		},
+	},
+	{
+		name: "Success Case - Drain Closes With Service Restart",
+		config: wspkg.Config{
+			Drain: wspkg.DrainConfig{GracePeriod: time.Second, ReconnectAfter: 3 * time.Second, Timeout: wstest.DefaultTimeout},
+		},
+		dial: []wstest.DialOption{wstest.WithUserID(1)},
+		scenario: func(t *testing.T, tester *tester_Handler, client *wstest.Client) {
+			drained := tester.drain()
+			frame := client.AwaitPayload(wspkg.PredefinedActionNotify, wspkg.NotifyPayloadServerRestarting)
+			if frame.ReconnectAfterMS != 3000 {
+				t.Fatalf("expected reconnect after 3000ms, got %d", frame.ReconnectAfterMS)
+			}
+			client.ExpectClose(fiberws.CloseServiceRestart)
+			tester.expectClosed(t, fiberws.CloseServiceRestart)
+			if err := <-drained; err != nil {
+				t.Fatalf("failed to drain: %v", err)
+			}
+		},
+	},
+	{
+		name: "Success Case - Drain Finishes In-Flight Message",
+		config: wspkg.Config{
+			Drain: wspkg.DrainConfig{GracePeriod: time.Second, Timeout: wstest.DefaultTimeout},
+		},
+		dial: []wstest.DialOption{wstest.WithUserID(1)},
+		scenario: func(t *testing.T, tester *tester_Handler, client *wstest.Client) {
+			client.Send(testActionSlow, "in-flight")
+			tester.awaitInFlight(t)
+			drained := tester.drain()
+			client.AwaitPayload(wspkg.PredefinedActionNotify, wspkg.NotifyPayloadServerRestarting)
+			client.AwaitPayload(wspkg.PredefinedActionData, "in-flight")
+			client.ExpectClose(fiberws.CloseServiceRestart)
+			if err := <-drained; err != nil {
+				t.Fatalf("failed to drain: %v", err)
+			}
+		},
+	},
+	{
+		name: "Success Case - Server Ping Keeps Connection",
+		config: wspkg.Config{
+			Heartbeat: wspkg.HeartbeatConfig{PingInterval: 50 * time.Millisecond, PongTimeout: time.Second},
+		},
+		dial: []wstest.DialOption{wstest.WithUserID(1)},
+		scenario: func(t *testing.T, tester *tester_Handler, client *wstest.Client) {
+			client.ExpectSilence(300 * time.Millisecond)
+			client.Send(testActionEcho, "alive")
+			client.AwaitPayload(wspkg.PredefinedActionData, "alive")
+			client.Close()
+		},
+	},
+	{
+		name: "Failure Case - Idle Connection Reaped",
+		config: wspkg.Config{
+			Heartbeat: wspkg.HeartbeatConfig{PingInterval: 50 * time.Millisecond, PongTimeout: time.Second, MaxIdle: 200 * time.Millisecond},
+		},
+		dial: []wstest.DialOption{wstest.WithUserID(1)},
+		scenario: func(t *testing.T, tester *tester_Handler, client *wstest.Client) {
+			client.ExpectClose(wspkg.CloseCodeIdleTimeout)
+			tester.expectClosed(t, wspkg.CloseCodeIdleTimeout)
+			if reaped := tester.hub.ReapedConnections.Load(); reaped != 1 {
+				t.Fatalf("expected 1 reaped connection, got %d", reaped)
+			}
+		},
+	},
+	{
+		name: "Failure Case - Lifetime Exceeded",
+		config: wspkg.Config{
+			Heartbeat: wspkg.HeartbeatConfig{PingInterval: 50 * time.Millisecond, PongTimeout: time.Second, MaxLifetime: 200 * time.Millisecond},
+		},
+		dial: []wstest.DialOption{wstest.WithUserID(1)},
+		scenario: func(t *testing.T, tester *tester_Handler, client *wstest.Client) {
+			for range 3 {
+				client.Send(testActionEcho, "busy")
+				client.AwaitPayload(wspkg.PredefinedActionData, "busy")
+				time.Sleep(50 * time.Millisecond)
+			}
+			client.ExpectClose(wspkg.CloseCodeLifetimeExceeded)
+			tester.expectClosed(t, wspkg.CloseCodeLifetimeExceeded)
+		},
+	},
+	{
+		name: "Failure Case - Throttled Action",
+		config: wspkg.Config{
+			RateLimit: wspkg.RateLimitConfig{
+				Actions: map[string]wspkg.ActionLimitConfig{string(testActionEcho): {Connection: wspkg.BucketConfig{Rate: 1, Burst: 1}}},
+			},
+		},
+		dial: []wstest.DialOption{wstest.WithUserID(1)},
+		scenario: func(t *testing.T, tester *tester_Handler, client *wstest.Client) {
+			client.Send(testActionEcho, "first")
+			client.AwaitPayload(wspkg.PredefinedActionData, "first")
+			client.Send(testActionEcho, "second")
+			frame := client.Await(wspkg.PredefinedActionReject)
+			payload, ok := frame.Payload.(map[string]any)
+			if !ok || payload["reason"] != wspkg.RejectReasonRateLimited || payload["retry_after_ms"] == nil {
+				t.Fatalf("expected %s rejection with retry after, got %v", wspkg.RejectReasonRateLimited, frame.Payload)
+			}
+			client.Close()
+		},
+	},
+	{
+		name: "Failure Case - Abusive Connection Closed",
+		config: wspkg.Config{
+			RateLimit: wspkg.RateLimitConfig{
+				AbuseThreshold: 2,
+				Actions:        map[string]wspkg.ActionLimitConfig{string(testActionEcho): {Connection: wspkg.BucketConfig{Rate: 0.01, Burst: 1}}},
+			},
+		},
+		dial: []wstest.DialOption{wstest.WithUserID(1)},
+		scenario: func(t *testing.T, tester *tester_Handler, client *wstest.Client) {
+			client.Send(testActionEcho, "first")
+			client.AwaitPayload(wspkg.PredefinedActionData, "first")
+			client.Send(testActionEcho, "second")
+			client.Await(wspkg.PredefinedActionReject)
+			client.Send(testActionEcho, "third")
+			client.ExpectClose(fiberws.ClosePolicyViolation)
+			tester.expectClosed(t, fiberws.ClosePolicyViolation)
+		},
+	},
+	{
+		name: "Failure Case - Message Too Big",
+		config: wspkg.Config{
+			RateLimit: wspkg.RateLimitConfig{MaxMessageSize: 64},
+		},
+		dial: []wstest.DialOption{wstest.WithUserID(1)},
+		scenario: func(t *testing.T, tester *tester_Handler, client *wstest.Client) {
+			client.Send(testActionEcho, strings.Repeat("x", 128))
+			client.ExpectClose(fiberws.CloseMessageTooBig)
+			tester.expectClosed(t, fiberws.CloseMessageTooBig)
+		},
+	},
+}
+
+func Test_Handler(t *testing.T) {
+	for _, testcase := range testcases_Handler {
+		t.Run(testcase.name, func(t *testing.T) {
+			tester := prepareHandlerForTest(testcase.config)
+			server := wstest.NewServer(t, tester, wstest.WithHub(tester.hub))
+			client := server.Dial(t, testcase.dial...)
+			client.AwaitPayload(wspkg.PredefinedActionNotify, testPayloadConnected)
+			testcase.scenario(t, tester, client)
+		})
+	}
+}
+
+type tester_Handler struct {
+	hub     *wspkg.Hub
+	closed  chan int
+	handled atomic.Int64
+}
+
+func prepareHandlerForTest(config wspkg.Config) *tester_Handler {
+	return &tester_Handler{
+		hub:    wspkg.NewHub(config, nil),
+		closed: make(chan int, 1),
+	}
+}
+
+func (tester *tester_Handler) Identify() string {
+	return "websocket-test"
+}
+
+func (tester *tester_Handler) HandleConnect(_ *fiberws.Conn, request wspkg.ConnectWrapper) (wspkg.ResponseWrapper, string, error) {
+	return wspkg.BuildResponseFrom(request, uuid.New().String(), wspkg.PredefinedActionNotify, testPayloadConnected), request.ConnectID, nil
+}
+
+func (tester *tester_Handler) HandleMessage(_ *fiberws.Conn, request wspkg.MessageWrapper) (wspkg.ResponseWrapper, bool, error) {
+	defer tester.handled.Add(1)
+	switch request.Action {
+	case testActionSlow:
+		time.Sleep(100 * time.Millisecond)
+		fallthrough
+	case testActionEcho:
+		return wspkg.BuildResponseFrom(request, uuid.New().String(), wspkg.PredefinedActionData, request.Payload), false, nil
+	default:
+		return wspkg.BuildErrorResponse(request, wspkg.RejectReasonUnknownAction, nil), false, nil
+	}
+}
+
+func (tester *tester_Handler) HandlePing(_ *fiberws.Conn, request wspkg.PingWrapper) (wspkg.ResponseWrapper, bool, error) {
+	return wspkg.BuildNoopResponse(request), false, nil
+}
+
+func (tester *tester_Handler) HandleClose(_ *fiberws.Conn, request wspkg.CloseWrapper) {
+	tester.closed <- request.CloseCode
+}
+
+func (tester *tester_Handler) expectClosed(t *testing.T, code int) {
+	t.Helper()
+	select {
+	case closed := <-tester.closed:
+		if closed != code {
+			t.Fatalf("expected handler to close with %d, got %d", code, closed)
+		}
+	case <-time.After(wstest.DefaultTimeout):
+		t.Fatalf("timed out waiting for handler to close")
+	}
+}
+
+func (tester *tester_Handler) drain() <-chan error {
+	drained := make(chan error, 1)
+	go func() {
+		drained <- tester.hub.Drain(context.Background())
+	}()
+	return drained
+}
+
+func (tester *tester_Handler) awaitInFlight(t *testing.T) {
+	t.Helper()
+	deadline := time.Now().Add(wstest.DefaultTimeout)
+	for tester.hub.InFlightMessages.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for in-flight message")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
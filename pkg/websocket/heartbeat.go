@@ -0,0 +1,65 @@
+package websocket
+
+import (
+	"time"
+
+	fiberws "github.com/gofiber/websocket/v2"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+)
+
+const (
+	CloseCodePongTimeout      = 4001
+	CloseCodeIdleTimeout      = 4002
+	CloseCodeLifetimeExceeded = 4003
+)
+
+const (
+	DefaultReapCycle = 1 * time.Minute
+)
+
+func startHeartbeat(conn *Connection, config HeartbeatConfig, sessionID string) func() {
+	if config.PingInterval > 0 {
+		conn.Conn.SetReadDeadline(time.Now().Add(config.PingInterval + config.PongTimeout))
+		conn.Conn.SetPongHandler(func(string) error {
+			return conn.Conn.SetReadDeadline(time.Now().Add(config.PingInterval + config.PongTimeout))
+		})
+	}
+
+	interval := config.PingInterval
+	if interval <= 0 {
+		if config.MaxIdle <= 0 && config.MaxLifetime <= 0 {
+			return func() {}
+		}
+		interval = DefaultReapCycle
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				if config.MaxLifetime > 0 && now.Sub(conn.ConnectedAt) >= config.MaxLifetime {
+					utils.Log(utils.InfoLevel).CID(sessionID).BT().Send("Reaping connection exceeding max lifetime")
+					conn.Reap(sessionID, CloseCodeLifetimeExceeded, "max lifetime exceeded")
+					return
+				}
+				if config.MaxIdle > 0 && now.Sub(conn.LastActiveAt()) >= config.MaxIdle {
+					utils.Log(utils.InfoLevel).CID(sessionID).BT().Send("Reaping idle connection")
+					conn.Reap(sessionID, CloseCodeIdleTimeout, "max idle time exceeded")
+					return
+				}
+				if config.PingInterval > 0 {
+					deadline := now.Add(max(config.PongTimeout, time.Second))
+					if err := conn.WriteControl(fiberws.PingMessage, nil, deadline); err != nil {
+						utils.Log(utils.DebugLevel).Err(err).CID(sessionID).BT().Send("Failed to send ping")
+					}
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
@@ -0,0 +1,393 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	fiberws "github.com/gofiber/websocket/v2"
+	"github.com/google/uuid"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+)
+
+const (
+	DefaultDedupWindow = 1 * time.Minute
+	DefaultOutboxSize  = 64
+)
+
+type OutboundFrame struct {
+	MessageType int
+	Data        []byte
+	Event       Action
+	EventID     int64
+	Retry       int64
+	Execute     func()
+	Result      chan error
+}
+
+type Connection struct {
+	SessionID   string
+	UserID      int64
+	Conn        *fiberws.Conn
+	Mutex       sync.Mutex
+	ConnectedAt time.Time
+	ActiveAt    atomic.Int64
+	ReapCode    atomic.Int32
+	CloseCode   atomic.Int32
+	TokenExpiry atomic.Int64
+	Buckets     map[Action]*TokenBucket
+	Violations  int
+	Codec       Codec
+	Outbox      chan OutboundFrame
+	Done        chan struct{}
+	DoneOnce    sync.Once
+	Pending     sync.WaitGroup
+}
+
+func NewConnection(c *fiberws.Conn, outboxSize int) *Connection {
+	if outboxSize <= 0 {
+		outboxSize = DefaultOutboxSize
+	}
+	now := time.Now()
+	conn := &Connection{
+		Conn:        c,
+		Codec:       JSONCodec{},
+		ConnectedAt: now,
+		Outbox:      make(chan OutboundFrame, outboxSize),
+		Done:        make(chan struct{}),
+	}
+	conn.ActiveAt.Store(now.UnixNano())
+	return conn
+}
+
+func (conn *Connection) StartWriter() func() {
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-conn.Done:
+				return
+			case frame := <-conn.Outbox:
+				if frame.Execute != nil {
+					frame.Execute()
+					frame.Result <- nil
+					continue
+				}
+				conn.Mutex.Lock()
+				err := conn.Conn.WriteMessage(frame.MessageType, frame.Data)
+				conn.Mutex.Unlock()
+				frame.Result <- err
+			}
+		}
+	}()
+	return func() {
+		conn.Close()
+		<-stopped
+	}
+}
+
+func (conn *Connection) Close() {
+	conn.DoneOnce.Do(func() { close(conn.Done) })
+}
+
+func (conn *Connection) Touch() {
+	conn.ActiveAt.Store(time.Now().UnixNano())
+}
+
+func (conn *Connection) LastActiveAt() time.Time {
+	return time.Unix(0, conn.ActiveAt.Load())
+}
+
+func (conn *Connection) Reap(sessionID string, code int, reason string) {
+	if conn.ReapCode.CompareAndSwap(0, int32(code)) {
+		closeConnection(conn, sessionID, "", reason, code)
+	}
+}
+
+func (conn *Connection) Reaped() (int, bool) {
+	code := conn.ReapCode.Load()
+	return int(code), code != 0
+}
+
+func (conn *Connection) SetTokenExpiry(expiry time.Time) {
+	if expiry.IsZero() {
+		conn.TokenExpiry.Store(0)
+		return
+	}
+	conn.TokenExpiry.Store(expiry.UnixNano())
+}
+
+func (conn *Connection) TokenExpiresAt() (time.Time, bool) {
+	expiry := conn.TokenExpiry.Load()
+	if expiry == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, expiry), true
+}
+
+func (conn *Connection) TokenExpired(now time.Time) bool {
+	expiresAt, ok := conn.TokenExpiresAt()
+	return ok && !now.Before(expiresAt)
+}
+
+func (conn *Connection) Terminate(sessionID string, messageID string, reason string, code int) {
+	if conn.CloseCode.CompareAndSwap(0, int32(code)) {
+		closeConnection(conn, sessionID, messageID, reason, code)
+	}
+}
+
+func (conn *Connection) Terminated() (int, bool) {
+	code := conn.CloseCode.Load()
+	return int(code), code != 0
+}
+
+func (conn *Connection) WriteMessage(messageType int, data []byte) error {
+	return conn.enqueue(OutboundFrame{
+		MessageType: messageType,
+		Data:        data,
+		Result:      make(chan error, 1),
+	})
+}
+
+func (conn *Connection) enqueue(frame OutboundFrame) error {
+	select {
+	case conn.Outbox <- frame:
+	case <-conn.Done:
+		return utils.NewError("connection writer is closed")
+	}
+	select {
+	case err := <-frame.Result:
+		return err
+	case <-conn.Done:
+		return utils.NewError("connection writer is closed")
+	}
+}
+
+func (conn *Connection) Schedule(job func()) bool {
+	frame := OutboundFrame{
+		Execute: job,
+		Result:  make(chan error, 1),
+	}
+	select {
+	case <-conn.Done:
+		return false
+	default:
+	}
+	select {
+	case conn.Outbox <- frame:
+		return true
+	default:
+		return false
+	}
+}
+
+func (conn *Connection) WriteControl(messageType int, data []byte, deadline time.Time) error {
+	conn.Mutex.Lock()
+	defer conn.Mutex.Unlock()
+	return conn.Conn.WriteControl(messageType, data, deadline)
+}
+
+func (conn *Connection) WriteResponse(response ResponseWrapper) error {
+	serialized, err := conn.Codec.Marshal(response)
+	if err != nil {
+		return utils.WrapError(err, "failed to serialize message")
+	}
+	err = conn.enqueue(OutboundFrame{
+		MessageType: conn.Codec.MessageType(),
+		Data:        serialized,
+		Event:       response.Action,
+		EventID:     response.Sequence,
+		Retry:       response.ReconnectAfterMS,
+		Result:      make(chan error, 1),
+	})
+	if err != nil {
+		return utils.WrapError(err, "failed to write message")
+	}
+	return nil
+}
+
+type Hub struct {
+	Config    Config
+	Backplane Backplane
+	Limiter   *RateLimiter
+	Sessions  map[string]map[*Connection]struct{}
+	Users     map[int64]map[*Connection]struct{}
+	Conns     map[*fiberws.Conn]*Connection
+	Workers   map[string]*SessionWorker
+	Mutex     sync.RWMutex
+
+	OpenConnections   atomic.Int64
+	ReapedConnections atomic.Int64
+	InFlightMessages  atomic.Int64
+	DrainState        atomic.Bool
+
+	Delivered   map[string]time.Time
+	DedupMutex  sync.Mutex
+	DedupWindow time.Duration
+}
+
+func NewHub(config Config, backplane Backplane) *Hub {
+	dedupWindow := config.Backplane.DedupWindow
+	if dedupWindow <= 0 {
+		dedupWindow = DefaultDedupWindow
+	}
+	return &Hub{
+		Config:      config,
+		Backplane:   backplane,
+		Limiter:     NewRateLimiter(config.RateLimit),
+		Sessions:    make(map[string]map[*Connection]struct{}),
+		Users:       make(map[int64]map[*Connection]struct{}),
+		Conns:       make(map[*fiberws.Conn]*Connection),
+		Workers:     make(map[string]*SessionWorker),
+		Delivered:   make(map[string]time.Time),
+		DedupWindow: dedupWindow,
+	}
+}
+
+func (h *Hub) Register(conn *Connection) {
+	h.Mutex.Lock()
+	defer h.Mutex.Unlock()
+	if _, ok := h.Sessions[conn.SessionID]; !ok {
+		h.Sessions[conn.SessionID] = make(map[*Connection]struct{})
+	}
+	h.Sessions[conn.SessionID][conn] = struct{}{}
+	if conn.UserID != 0 {
+		if _, ok := h.Users[conn.UserID]; !ok {
+			h.Users[conn.UserID] = make(map[*Connection]struct{})
+		}
+		h.Users[conn.UserID][conn] = struct{}{}
+	}
+	if conn.Conn != nil {
+		h.Conns[conn.Conn] = conn
+	}
+}
+
+func (h *Hub) Unregister(conn *Connection) {
+	h.Mutex.Lock()
+	defer h.Mutex.Unlock()
+	if connections, ok := h.Sessions[conn.SessionID]; ok {
+		delete(connections, conn)
+		if len(connections) == 0 {
+			delete(h.Sessions, conn.SessionID)
+		}
+	}
+	if connections, ok := h.Users[conn.UserID]; ok {
+		delete(connections, conn)
+		if len(connections) == 0 {
+			delete(h.Users, conn.UserID)
+		}
+	}
+	if conn.Conn != nil {
+		delete(h.Conns, conn.Conn)
+	}
+}
+
+func (h *Hub) Lookup(c *fiberws.Conn) (*Connection, error) {
+	h.Mutex.RLock()
+	defer h.Mutex.RUnlock()
+	conn, ok := h.Conns[c]
+	if !ok {
+		return nil, utils.NewError("connection is not registered")
+	}
+	return conn, nil
+}
+
+func (h *Hub) SessionConnections(sessionID string) int {
+	h.Mutex.RLock()
+	defer h.Mutex.RUnlock()
+	return len(h.Sessions[sessionID])
+}
+
+func (h *Hub) Publish(userID int64, response ResponseWrapper) error {
+	return h.publish(Envelope{UserID: userID, Response: response})
+}
+
+func (h *Hub) PublishSession(sessionID string, response ResponseWrapper) error {
+	return h.publish(Envelope{SessionID: sessionID, Response: response})
+}
+
+func (h *Hub) Deliver(envelope Envelope) {
+	if !h.markDelivered(envelope.ID) {
+		return
+	}
+	h.Mutex.RLock()
+	connections := []*Connection(nil)
+	if envelope.SessionID != "" {
+		connections = collectConnections(h.Sessions[envelope.SessionID])
+	} else {
+		connections = collectConnections(h.Users[envelope.UserID])
+	}
+	h.Mutex.RUnlock()
+	deliverResponse(connections, envelope.Response)
+}
+
+func (h *Hub) publish(envelope Envelope) error {
+	if envelope.Response.MessageID == "" {
+		envelope.Response.MessageID = uuid.New().String()
+	}
+	envelope.ID = envelope.Response.MessageID
+	if h.Backplane == nil {
+		h.Deliver(envelope)
+		return nil
+	}
+	if err := h.Backplane.Publish(context.Background(), envelope); err != nil {
+		return utils.WrapError(err, "failed to publish to backplane")
+	}
+	return nil
+}
+
+func (h *Hub) markDelivered(id string) bool {
+	h.DedupMutex.Lock()
+	defer h.DedupMutex.Unlock()
+	now := time.Now()
+	if deliveredAt, ok := h.Delivered[id]; ok && now.Sub(deliveredAt) < h.DedupWindow {
+		return false
+	}
+	for key, deliveredAt := range h.Delivered {
+		if now.Sub(deliveredAt) >= h.DedupWindow {
+			delete(h.Delivered, key)
+		}
+	}
+	h.Delivered[id] = now
+	return true
+}
+
+type HubStatistics struct {
+	OpenConnections   int64
+	ReapedConnections int64
+	Sessions          int
+	Users             int
+}
+
+func (h *Hub) Statistics() HubStatistics {
+	h.Mutex.RLock()
+	defer h.Mutex.RUnlock()
+	return HubStatistics{
+		OpenConnections:   h.OpenConnections.Load(),
+		ReapedConnections: h.ReapedConnections.Load(),
+		Sessions:          len(h.Sessions),
+		Users:             len(h.Users),
+	}
+}
+
+func collectConnections(connections map[*Connection]struct{}) []*Connection {
+	results := make([]*Connection, 0, len(connections))
+	for conn := range connections {
+		results = append(results, conn)
+	}
+	return results
+}
+
+func deliverResponse(connections []*Connection, response ResponseWrapper) {
+	for _, conn := range connections {
+		pushed := response
+		if pushed.SessionID == "" {
+			pushed.SessionID = conn.SessionID
+		}
+		if err := conn.WriteResponse(pushed); err != nil {
+			utils.Log(utils.WarnLevel).Err(err).CID(conn.SessionID).BT().Send("Failed to deliver message")
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package websocket
+
+import (
+	"testing"
+	"time"
+)
+
+var testcases_Hub = []struct {
+	name     string
+	scenario func(t *testing.T, tester *tester_Hub)
+}{
+	{
+		name: "Success Case - Publish Session",
+		scenario: func(t *testing.T, tester *tester_Hub) {
+			first := tester.connect(t, "session-1", 1)
+			second := tester.connect(t, "session-2", 1)
+
+			if err := tester.hub.PublishSession("session-1", BuildPushResponse("", PredefinedActionNotify, "hello")); err != nil {
+				t.Fatalf("failed to publish: %v", err)
+			}
+			response := first.expect(t)
+			if response.Payload != "hello" || response.SessionID != "session-1" {
+				t.Fatalf("expected hello on session-1, got %+v", response)
+			}
+			second.expectNone(t)
+		},
+	},
+	{
+		name: "Success Case - Publish User",
+		scenario: func(t *testing.T, tester *tester_Hub) {
+			first := tester.connect(t, "session-1", 1)
+			second := tester.connect(t, "session-2", 1)
+			other := tester.connect(t, "session-3", 2)
+
+			if err := tester.hub.Publish(1, BuildPushResponse("", PredefinedActionNotify, "hello")); err != nil {
+				t.Fatalf("failed to publish: %v", err)
+			}
+			if response := first.expect(t); response.SessionID != "session-1" {
+				t.Fatalf("expected session-1 to be stamped, got %+v", response)
+			}
+			if response := second.expect(t); response.SessionID != "session-2" {
+				t.Fatalf("expected session-2 to be stamped, got %+v", response)
+			}
+			other.expectNone(t)
+		},
+	},
+	{
+		name: "Success Case - Deliver Drops Duplicates",
+		scenario: func(t *testing.T, tester *tester_Hub) {
+			conn := tester.connect(t, "session-1", 1)
+
+			envelope := Envelope{ID: "message-1", SessionID: "session-1", Response: BuildPushResponse("session-1", PredefinedActionNotify, "hello")}
+			tester.hub.Deliver(envelope)
+			tester.hub.Deliver(envelope)
+			conn.expect(t)
+			conn.expectNone(t)
+		},
+	},
+	{
+		name: "Success Case - Unregister Stops Delivery",
+		scenario: func(t *testing.T, tester *tester_Hub) {
+			conn := tester.connect(t, "session-1", 1)
+			tester.hub.Unregister(conn.Connection)
+
+			if err := tester.hub.PublishSession("session-1", BuildPushResponse("", PredefinedActionNotify, "hello")); err != nil {
+				t.Fatalf("failed to publish: %v", err)
+			}
+			conn.expectNone(t)
+			if statistics := tester.hub.Statistics(); statistics.Sessions != 0 || statistics.Users != 0 {
+				t.Fatalf("expected empty hub, got %+v", statistics)
+			}
+		},
+	},
+}
+
+func Test_Hub(t *testing.T) {
+	for _, testcase := range testcases_Hub {
+		t.Run(testcase.name, func(t *testing.T) {
+			tester := &tester_Hub{hub: NewHub(Config{}, nil)}
+			testcase.scenario(t, tester)
+		})
+	}
+}
+
+type tester_Hub struct {
+	hub *Hub
+}
+
+type tester_HubConnection struct {
+	*Connection
+	Responses chan ResponseWrapper
+}
+
+// connect registers a connection whose outbox is consumed in place of a socket writer.
+func (tester *tester_Hub) connect(t *testing.T, sessionID string, userID int64) *tester_HubConnection {
+	conn := NewConnection(nil, 0)
+	conn.SessionID = sessionID
+	conn.UserID = userID
+	responses := make(chan ResponseWrapper, 8)
+	go func() {
+		for {
+			select {
+			case <-conn.Done:
+				return
+			case frame := <-conn.Outbox:
+				var response ResponseWrapper
+				frame.Result <- conn.Codec.Unmarshal(frame.Data, &response)
+				responses <- response
+			}
+		}
+	}()
+	t.Cleanup(conn.Close)
+	tester.hub.Register(conn)
+	return &tester_HubConnection{Connection: conn, Responses: responses}
+}
+
+func (conn *tester_HubConnection) expect(t *testing.T) ResponseWrapper {
+	t.Helper()
+	select {
+	case response := <-conn.Responses:
+		return response
+	case <-time.After(time.Second):
+		t.Fatalf("timed out waiting for response on %s", conn.SessionID)
+		return ResponseWrapper{}
+	}
+}
+
+func (conn *tester_HubConnection) expectNone(t *testing.T) {
+	t.Helper()
+	select {
+	case response := <-conn.Responses:
+		t.Fatalf("expected no response on %s, got %+v", conn.SessionID, response)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
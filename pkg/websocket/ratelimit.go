@@ -0,0 +1,199 @@
+package websocket
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	RateLimitWildcardAction = "*"
+	DefaultRateLimitIdleTTL = 10 * time.Minute
+)
+
+type TokenBucket struct {
+	Rate      float64
+	Burst     float64
+	Tokens    float64
+	UpdatedAt time.Time
+	Mutex     sync.Mutex
+}
+
+func NewTokenBucket(config BucketConfig) *TokenBucket {
+	return &TokenBucket{
+		Rate:      config.Rate,
+		Burst:     float64(config.Burst),
+		Tokens:    float64(config.Burst),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func (b *TokenBucket) Take(now time.Time) (bool, time.Duration) {
+	return TakeAll(now, b)
+}
+
+// TakeAll consumes a token from every bucket only when all of them have one to spare.
+// Callers must pass buckets in a consistent order, connection before user.
+func TakeAll(now time.Time, buckets ...*TokenBucket) (bool, time.Duration) {
+	for _, bucket := range buckets {
+		bucket.Mutex.Lock()
+		defer bucket.Mutex.Unlock()
+	}
+	retryAfter := time.Duration(0)
+	for _, bucket := range buckets {
+		bucket.refill(now)
+		if bucket.Tokens >= 1 {
+			continue
+		}
+		if bucket.Rate <= 0 {
+			return false, time.Duration(math.MaxInt64)
+		}
+		retryAfter = max(retryAfter, time.Duration((1-bucket.Tokens)/bucket.Rate*float64(time.Second)))
+	}
+	if retryAfter > 0 {
+		return false, retryAfter
+	}
+	for _, bucket := range buckets {
+		bucket.Tokens--
+	}
+	return true, 0
+}
+
+func (b *TokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.UpdatedAt).Seconds()
+	if elapsed > 0 {
+		b.Tokens = math.Min(b.Burst, b.Tokens+elapsed*b.Rate)
+		b.UpdatedAt = now
+	}
+}
+
+func (b *TokenBucket) isIdle(now time.Time, ttl time.Duration) bool {
+	b.Mutex.Lock()
+	defer b.Mutex.Unlock()
+	idle := now.Sub(b.UpdatedAt)
+	// A bucket that has not refilled yet still carries debt, so dropping it would reset the limit early.
+	return idle >= ttl && b.Tokens+idle.Seconds()*b.Rate >= b.Burst
+}
+
+type RateLimiter struct {
+	Config         RateLimitConfig
+	UserBuckets    map[int64]map[Action]*TokenBucket
+	SessionBuckets map[string]map[Action]*TokenBucket
+	SweptAt        time.Time
+	Mutex          sync.Mutex
+}
+
+func NewRateLimiter(config RateLimitConfig) *RateLimiter {
+	if config.IdleTTL <= 0 {
+		config.IdleTTL = DefaultRateLimitIdleTTL
+	}
+	return &RateLimiter{
+		Config:         config,
+		UserBuckets:    make(map[int64]map[Action]*TokenBucket),
+		SessionBuckets: make(map[string]map[Action]*TokenBucket),
+		SweptAt:        time.Now(),
+	}
+}
+
+func (l *RateLimiter) Allow(conn *Connection, action Action) (bool, time.Duration) {
+	limit, ok := l.limitOf(action)
+	if !ok {
+		return true, 0
+	}
+	now := time.Now()
+	buckets := []*TokenBucket{}
+	if limit.Connection.Burst > 0 {
+		buckets = append(buckets, conn.bucketOf(action, limit.Connection))
+	}
+	if limit.User.Burst > 0 && conn.UserID != 0 {
+		buckets = append(buckets, l.userBucketOf(now, conn.UserID, action, limit.User))
+	}
+	return TakeAll(now, buckets...)
+}
+
+// AllowSession applies the connection limit per session for transports that have no long-lived connection.
+func (l *RateLimiter) AllowSession(userID int64, sessionID string, action Action) (bool, time.Duration) {
+	limit, ok := l.limitOf(action)
+	if !ok {
+		return true, 0
+	}
+	now := time.Now()
+	buckets := []*TokenBucket{}
+	if limit.Connection.Burst > 0 && sessionID != "" {
+		buckets = append(buckets, l.sessionBucketOf(now, sessionID, action, limit.Connection))
+	}
+	if limit.User.Burst > 0 && userID != 0 {
+		buckets = append(buckets, l.userBucketOf(now, userID, action, limit.User))
+	}
+	return TakeAll(now, buckets...)
+}
+
+func (l *RateLimiter) limitOf(action Action) (ActionLimitConfig, bool) {
+	limit, ok := l.Config.Actions[string(action)]
+	if !ok {
+		limit, ok = l.Config.Actions[RateLimitWildcardAction]
+	}
+	return limit, ok
+}
+
+func (l *RateLimiter) userBucketOf(now time.Time, userID int64, action Action, config BucketConfig) *TokenBucket {
+	l.Mutex.Lock()
+	defer l.Mutex.Unlock()
+	l.sweep(now)
+	return keyedBucketOf(l.UserBuckets, userID, action, config)
+}
+
+func (l *RateLimiter) sessionBucketOf(now time.Time, sessionID string, action Action, config BucketConfig) *TokenBucket {
+	l.Mutex.Lock()
+	defer l.Mutex.Unlock()
+	l.sweep(now)
+	return keyedBucketOf(l.SessionBuckets, sessionID, action, config)
+}
+
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.SweptAt) < l.Config.IdleTTL {
+		return
+	}
+	sweepIdleBuckets(l.UserBuckets, now, l.Config.IdleTTL)
+	sweepIdleBuckets(l.SessionBuckets, now, l.Config.IdleTTL)
+	l.SweptAt = now
+}
+
+func keyedBucketOf[K comparable](keyed map[K]map[Action]*TokenBucket, key K, action Action, config BucketConfig) *TokenBucket {
+	if _, ok := keyed[key]; !ok {
+		keyed[key] = make(map[Action]*TokenBucket)
+	}
+	bucket, ok := keyed[key][action]
+	if !ok {
+		bucket = NewTokenBucket(config)
+		keyed[key][action] = bucket
+	}
+	return bucket
+}
+
+func sweepIdleBuckets[K comparable](keyed map[K]map[Action]*TokenBucket, now time.Time, ttl time.Duration) {
+	for key, buckets := range keyed {
+		for action, bucket := range buckets {
+			if bucket.isIdle(now, ttl) {
+				delete(buckets, action)
+			}
+		}
+		if len(buckets) == 0 {
+			delete(keyed, key)
+		}
+	}
+}
+
+func (conn *Connection) bucketOf(action Action, config BucketConfig) *TokenBucket {
+	conn.Mutex.Lock()
+	defer conn.Mutex.Unlock()
+	if conn.Buckets == nil {
+		conn.Buckets = make(map[Action]*TokenBucket)
+	}
+	bucket, ok := conn.Buckets[action]
+	if !ok {
+		bucket = NewTokenBucket(config)
+		conn.Buckets[action] = bucket
+	}
+	return bucket
+}
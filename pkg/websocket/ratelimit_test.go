@@ -0,0 +1,158 @@
+package websocket
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+var testcases_TakeAll = []struct {
+	name       string
+	buckets    []BucketConfig
+	takes      int
+	allowed    bool
+	retryAfter time.Duration
+	remaining  []float64
+}{
+	{
+		name:      "Success Case - Within Burst",
+		buckets:   []BucketConfig{{Rate: 1, Burst: 2}},
+		takes:     2,
+		allowed:   true,
+		remaining: []float64{0},
+	},
+	{
+		name:       "Failure Case - Burst Exhausted",
+		buckets:    []BucketConfig{{Rate: 2, Burst: 1}},
+		takes:      2,
+		retryAfter: 500 * time.Millisecond,
+		remaining:  []float64{0},
+	},
+	{
+		name:       "Failure Case - No Refill",
+		buckets:    []BucketConfig{{Rate: 0, Burst: 1}},
+		takes:      2,
+		retryAfter: time.Duration(math.MaxInt64),
+		remaining:  []float64{0},
+	},
+	{
+		name:       "Failure Case - One Bucket Empty Consumes None",
+		buckets:    []BucketConfig{{Rate: 1, Burst: 3}, {Rate: 1, Burst: 1}},
+		takes:      2,
+		retryAfter: time.Second,
+		remaining:  []float64{2, 0},
+	},
+}
+
+func Test_TakeAll(t *testing.T) {
+	for _, testcase := range testcases_TakeAll {
+		t.Run(testcase.name, func(t *testing.T) {
+			now := time.Now()
+			buckets := []*TokenBucket{}
+			for _, config := range testcase.buckets {
+				bucket := NewTokenBucket(config)
+				bucket.UpdatedAt = now
+				buckets = append(buckets, bucket)
+			}
+			allowed, retryAfter := false, time.Duration(0)
+			for range testcase.takes {
+				allowed, retryAfter = TakeAll(now, buckets...)
+			}
+			if allowed != testcase.allowed || retryAfter != testcase.retryAfter {
+				t.Fatalf("expected (%v, %v), got (%v, %v)", testcase.allowed, testcase.retryAfter, allowed, retryAfter)
+			}
+			for i, bucket := range buckets {
+				if bucket.Tokens != testcase.remaining[i] {
+					t.Fatalf("expected bucket %d to hold %v tokens, got %v", i, testcase.remaining[i], bucket.Tokens)
+				}
+			}
+		})
+	}
+}
+
+func Test_TokenBucket_Refill(t *testing.T) {
+	now := time.Now()
+	bucket := NewTokenBucket(BucketConfig{Rate: 2, Burst: 2})
+	bucket.UpdatedAt = now
+	bucket.Take(now)
+	bucket.Take(now)
+
+	if allowed, _ := bucket.Take(now.Add(250 * time.Millisecond)); allowed {
+		t.Fatalf("expected half a token to be refused")
+	}
+	if allowed, _ := bucket.Take(now.Add(500 * time.Millisecond)); !allowed {
+		t.Fatalf("expected a refilled token to be allowed")
+	}
+	bucket.refill(now.Add(time.Hour))
+	if bucket.Tokens != bucket.Burst {
+		t.Fatalf("expected refill to cap at burst, got %v", bucket.Tokens)
+	}
+}
+
+var testcases_RateLimiter = []struct {
+	name     string
+	config   RateLimitConfig
+	action   Action
+	attempts int
+	allowed  int
+}{
+	{
+		name:     "Success Case - Unlimited Action",
+		config:   RateLimitConfig{Actions: map[string]ActionLimitConfig{"chat": {Connection: BucketConfig{Rate: 0, Burst: 1}}}},
+		action:   "typing_start",
+		attempts: 5,
+		allowed:  5,
+	},
+	{
+		name:     "Success Case - Wildcard Action Per Connection",
+		config:   RateLimitConfig{Actions: map[string]ActionLimitConfig{RateLimitWildcardAction: {Connection: BucketConfig{Rate: 0, Burst: 2}}}},
+		action:   "typing_start",
+		attempts: 6,
+		allowed:  4,
+	},
+	{
+		name:     "Success Case - User Limit Shared Across Connections",
+		config:   RateLimitConfig{Actions: map[string]ActionLimitConfig{"chat": {Connection: BucketConfig{Rate: 0, Burst: 5}, User: BucketConfig{Rate: 0, Burst: 3}}}},
+		action:   "chat",
+		attempts: 5,
+		allowed:  3,
+	},
+}
+
+func Test_RateLimiter(t *testing.T) {
+	for _, testcase := range testcases_RateLimiter {
+		t.Run(testcase.name, func(t *testing.T) {
+			limiter := NewRateLimiter(testcase.config)
+			connections := []*Connection{{UserID: 1}, {UserID: 1}}
+			allowed := 0
+			for i := range testcase.attempts {
+				if ok, _ := limiter.Allow(connections[i%len(connections)], testcase.action); ok {
+					allowed++
+				}
+			}
+			if allowed != testcase.allowed {
+				t.Fatalf("expected %d allowed, got %d", testcase.allowed, allowed)
+			}
+		})
+	}
+}
+
+func Test_RateLimiter_Sweep(t *testing.T) {
+	limiter := NewRateLimiter(RateLimitConfig{IdleTTL: time.Minute})
+	now := time.Now()
+	full := NewTokenBucket(BucketConfig{Rate: 1, Burst: 1})
+	full.UpdatedAt = now
+	indebted := NewTokenBucket(BucketConfig{Rate: 0, Burst: 1})
+	indebted.Tokens = 0
+	indebted.UpdatedAt = now
+	limiter.UserBuckets[1] = map[Action]*TokenBucket{"chat": full}
+	limiter.UserBuckets[2] = map[Action]*TokenBucket{"chat": indebted}
+
+	limiter.sweep(now.Add(2 * time.Minute))
+	if _, ok := limiter.UserBuckets[1]; ok {
+		t.Fatalf("expected idle full bucket to be evicted")
+	}
+	if _, ok := limiter.UserBuckets[2]; !ok {
+		t.Fatalf("expected bucket still in debt to be kept")
+	}
+}
@@ -0,0 +1,110 @@
+package websocket
+
+import (
+	"encoding/json"
+	"sort"
+
+	fiberws "github.com/gofiber/websocket/v2"
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+)
+
+type ActionHandler[P any] func(c *fiberws.Conn, request MessageWrapper, payload P) (ResponseWrapper, bool, error)
+
+type ActionValidator[P any] func(payload P) error
+
+type ActionGuard func(c *fiberws.Conn, request MessageWrapper) (ResponseWrapper, bool)
+
+type ActionRoute struct {
+	Action    Action
+	Immediate bool
+	Invoke    func(c *fiberws.Conn, request MessageWrapper) (ResponseWrapper, bool, error)
+}
+
+type Router struct {
+	Routes map[Action]ActionRoute
+	Guards []ActionGuard
+}
+
+func NewRouter() *Router {
+	return &Router{
+		Routes: make(map[Action]ActionRoute),
+	}
+}
+
+func (r *Router) Use(guards ...ActionGuard) *Router {
+	r.Guards = append(r.Guards, guards...)
+	return r
+}
+
+func Register[P any](router *Router, action Action, handler ActionHandler[P], validators ...ActionValidator[P]) *Router {
+	return register(router, action, false, handler, validators...)
+}
+
+func RegisterImmediate[P any](router *Router, action Action, handler ActionHandler[P], validators ...ActionValidator[P]) *Router {
+	return register(router, action, true, handler, validators...)
+}
+
+func register[P any](router *Router, action Action, immediate bool, handler ActionHandler[P], validators ...ActionValidator[P]) *Router {
+	router.Routes[action] = ActionRoute{
+		Action:    action,
+		Immediate: immediate,
+		Invoke: func(c *fiberws.Conn, request MessageWrapper) (ResponseWrapper, bool, error) {
+			payload, err := DecodePayload[P](request.Payload)
+			if err != nil {
+				utils.Log(utils.InfoLevel).Err(err).CID(request.SessionID).RID(request.MessageID).BT().Send("Malformed payload for %v", action)
+				return BuildErrorResponse(request, RejectReasonInvalidPayload, err), false, nil
+			}
+			for _, validate := range validators {
+				if err := validate(payload); err != nil {
+					utils.Log(utils.InfoLevel).Err(err).CID(request.SessionID).RID(request.MessageID).BT().Send("Invalid payload for %v", action)
+					return BuildErrorResponse(request, RejectReasonInvalidPayload, err), false, nil
+				}
+			}
+			return handler(c, request, payload)
+		},
+	}
+	return router
+}
+
+func (r *Router) Dispatch(c *fiberws.Conn, request MessageWrapper) (ResponseWrapper, bool, error) {
+	for _, guard := range r.Guards {
+		if response, ok := guard(c, request); !ok {
+			return response, false, nil
+		}
+	}
+	route, ok := r.Routes[request.Action]
+	if !ok {
+		utils.Log(utils.InfoLevel).CID(request.SessionID).RID(request.MessageID).BT().Send("Unknown action %v", request.Action)
+		return BuildErrorResponse(request, RejectReasonUnknownAction, utils.NewError("unknown action %v", request.Action)), false, nil
+	}
+	return route.Invoke(c, request)
+}
+
+func (r *Router) IsImmediateAction(action Action) bool {
+	route, ok := r.Routes[action]
+	return ok && route.Immediate
+}
+
+func (r *Router) Actions() []Action {
+	actions := make([]Action, 0, len(r.Routes))
+	for action := range r.Routes {
+		actions = append(actions, action)
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i] < actions[j] })
+	return actions
+}
+
+func DecodePayload[P any](raw any) (P, error) {
+	var payload P
+	if typed, ok := raw.(P); ok {
+		return typed, nil
+	}
+	marshaled, err := json.Marshal(raw)
+	if err != nil {
+		return payload, utils.WrapError(err, "failed to marshal payload")
+	}
+	if err := json.Unmarshal(marshaled, &payload); err != nil {
+		return payload, utils.WrapError(err, "failed to unmarshal payload into %T", payload)
+	}
+	return payload, nil
+}
@@ -0,0 +1,97 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/solutionchallenge/ondaum-server/pkg/utils"
+)
+
+const (
+	DefaultStreamKeepAlive = 15 * time.Second
+)
+
+func NewStreamConnection(sessionID string, userID int64, outboxSize int) *Connection {
+	conn := NewConnection(nil, outboxSize)
+	conn.SessionID = sessionID
+	conn.UserID = userID
+	return conn
+}
+
+func (h *Hub) ServeStream(conn *Connection, w *bufio.Writer, ready func()) error {
+	h.OpenConnections.Add(1)
+	defer h.OpenConnections.Add(-1)
+	h.Register(conn)
+	defer h.Unregister(conn)
+	stopAuthWatch := startAuthWatch(conn, h.Config.Auth, conn.SessionID)
+	defer stopAuthWatch()
+	if ready != nil {
+		go ready()
+	}
+	return conn.ServeStream(w, h.Config.Heartbeat.PingInterval)
+}
+
+func (conn *Connection) ServeStream(w *bufio.Writer, keepAlive time.Duration) error {
+	defer conn.Close()
+	if keepAlive <= 0 {
+		keepAlive = DefaultStreamKeepAlive
+	}
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+
+	if err := flushStream(w, []byte(": connected\n\n")); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-conn.Done:
+			return nil
+		case <-ticker.C:
+			if err := flushStream(w, []byte(": keepalive\n\n")); err != nil {
+				return err
+			}
+		case frame := <-conn.Outbox:
+			if frame.Execute != nil {
+				frame.Execute()
+				frame.Result <- nil
+				continue
+			}
+			err := flushStream(w, FormatStreamEvent(frame))
+			frame.Result <- err
+			if err != nil {
+				return err
+			}
+			conn.Touch()
+		}
+	}
+}
+
+func FormatStreamEvent(frame OutboundFrame) []byte {
+	buffer := bytes.Buffer{}
+	if frame.EventID > 0 {
+		fmt.Fprintf(&buffer, "id: %d\n", frame.EventID)
+	}
+	if frame.Retry > 0 {
+		fmt.Fprintf(&buffer, "retry: %d\n", frame.Retry)
+	}
+	if frame.Event != "" {
+		fmt.Fprintf(&buffer, "event: %s\n", frame.Event)
+	}
+	for _, line := range bytes.Split(frame.Data, []byte("\n")) {
+		fmt.Fprintf(&buffer, "data: %s\n", line)
+	}
+	buffer.WriteString("\n")
+	return buffer.Bytes()
+}
+
+func flushStream(w *bufio.Writer, data []byte) error {
+	if _, err := w.Write(data); err != nil {
+		return utils.WrapError(err, "failed to write stream event")
+	}
+	if err := w.Flush(); err != nil {
+		return utils.WrapError(err, "failed to flush stream event")
+	}
+	return nil
+}
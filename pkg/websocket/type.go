@@ -1,6 +1,11 @@
 package websocket
 
-import "github.com/google/uuid"
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type Action string
 
@@ -9,8 +14,27 @@ const (
 	PredefinedActionReject Action = "reject"
 	PredefinedActionData   Action = "data"
 	PredefinedActionNotify Action = "notify"
+
+	PredefinedActionAuthenticate Action = "authenticate"
+	PredefinedActionAuthExpiring Action = "auth_expiring"
+)
+
+const (
+	RejectReasonRateLimited    = "rate_limited"
+	RejectReasonUnknownAction  = "unknown_action"
+	RejectReasonInvalidPayload = "invalid_payload"
+	RejectReasonBusy           = "busy"
+	RejectReasonRestarting     = "server_restarting"
+	RejectReasonUnauthorized   = "unauthorized"
 )
 
+type RejectPayload struct {
+	Reason       string `json:"reason"`
+	Action       Action `json:"action,omitempty"`
+	RetryAfterMS int64  `json:"retry_after_ms,omitempty"`
+	Message      string `json:"message,omitempty"`
+}
+
 type ControlFlag string
 
 const (
@@ -18,8 +42,14 @@ const (
 	ControlFlagClose = ControlFlag("close")
 )
 
+type InboundFrame struct {
+	Action  Action `json:"action"`
+	Payload any    `json:"payload"`
+}
+
 type ConnectWrapper struct {
 	ConnectID string
+	Protocol  string
 
 	Authorized   bool
 	UserID       int64
@@ -30,8 +60,9 @@ type MessageWrapper struct {
 	Action  Action
 	Payload any
 
-	SessionID string
-	MessageID string
+	SessionID  string
+	MessageID  string
+	ReceivedAt time.Time
 
 	Authorized   bool
 	UserID       int64
@@ -63,6 +94,10 @@ type ResponseWrapper struct {
 
 	SessionID string `json:"session_id"`
 	MessageID string `json:"message_id"`
+	Sequence  int64  `json:"sequence,omitempty"`
+	Protocol  string `json:"protocol,omitempty"`
+
+	ReconnectAfterMS int64 `json:"reconnect_after_ms,omitempty"`
 
 	ControlFlags []ControlFlag `json:"-"`
 }
@@ -118,3 +153,37 @@ func BuildRejectResponse[WRAP MessageWrapper | ConnectWrapper | PingWrapper | Cl
 		ControlFlagClose,
 	)
 }
+
+func BuildPushResponse(sessionID string, action Action, payload any) ResponseWrapper {
+	return ResponseWrapper{
+		Action:    action,
+		Payload:   payload,
+		SessionID: sessionID,
+		MessageID: uuid.New().String(),
+	}
+}
+
+func BuildThrottleResponse(request MessageWrapper, retryAfter time.Duration) ResponseWrapper {
+	return BuildResponseFrom(request,
+		uuid.New().String(),
+		PredefinedActionReject, RejectPayload{
+			Reason:       RejectReasonRateLimited,
+			Action:       request.Action,
+			RetryAfterMS: retryAfter.Milliseconds(),
+		},
+	)
+}
+
+func BuildErrorResponse(request MessageWrapper, reason string, err error) ResponseWrapper {
+	payload := RejectPayload{
+		Reason: reason,
+		Action: request.Action,
+	}
+	for cause := err; cause != nil; cause = errors.Unwrap(cause) {
+		payload.Message = cause.Error()
+	}
+	return BuildResponseFrom(request,
+		uuid.New().String(),
+		PredefinedActionReject, payload,
+	)
+}
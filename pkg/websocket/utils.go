@@ -2,6 +2,7 @@ package websocket
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	fiberws "github.com/gofiber/websocket/v2"
@@ -74,3 +75,24 @@ func GetWebsocketSessionID[T *fiberws.Conn | *fiber.Ctx](c T) (string, error) {
 	}
 	return stringValue, nil
 }
+
+func GetWebsocketTokenExpiry[T *fiberws.Conn | *fiber.Ctx](c T) (time.Time, error) {
+	localValue := any(nil)
+	switch c := any(c).(type) {
+	case *fiberws.Conn:
+		localValue = c.Locals("X-Websocket-Token-Expiry")
+		if localValue == nil {
+			return time.Time{}, utils.NewError("X-Websocket-Token-Expiry is not set")
+		}
+	case *fiber.Ctx:
+		localValue = c.Locals("X-Websocket-Token-Expiry")
+		if localValue == nil {
+			return time.Time{}, utils.NewError("X-Websocket-Token-Expiry is not set")
+		}
+	}
+	expiry, ok := localValue.(time.Time)
+	if !ok {
+		return time.Time{}, utils.NewError("X-Websocket-Token-Expiry is not a time")
+	}
+	return expiry, nil
+}
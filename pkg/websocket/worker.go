@@ -0,0 +1,59 @@
+package websocket
+
+const (
+	DefaultWorkerQueueSize = 16
+)
+
+type SessionWorker struct {
+	SessionID  string
+	Jobs       chan func()
+	References int
+}
+
+func (w *SessionWorker) Submit(job func()) bool {
+	select {
+	case w.Jobs <- job:
+		return true
+	default:
+		return false
+	}
+}
+
+func (w *SessionWorker) run() {
+	for job := range w.Jobs {
+		job()
+	}
+}
+
+func (h *Hub) AcquireWorker(sessionID string) *SessionWorker {
+	h.Mutex.Lock()
+	defer h.Mutex.Unlock()
+	worker, ok := h.Workers[sessionID]
+	if !ok {
+		queueSize := h.Config.Worker.QueueSize
+		if queueSize <= 0 {
+			queueSize = DefaultWorkerQueueSize
+		}
+		worker = &SessionWorker{
+			SessionID: sessionID,
+			Jobs:      make(chan func(), queueSize),
+		}
+		h.Workers[sessionID] = worker
+		go worker.run()
+	}
+	worker.References++
+	return worker
+}
+
+func (h *Hub) ReleaseWorker(worker *SessionWorker) {
+	h.Mutex.Lock()
+	defer h.Mutex.Unlock()
+	worker.References--
+	if worker.References > 0 {
+		return
+	}
+	if h.Workers[worker.SessionID] == worker {
+		delete(h.Workers, worker.SessionID)
+	}
+	close(worker.Jobs)
+}
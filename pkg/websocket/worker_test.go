@@ -0,0 +1,104 @@
+package websocket
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_SessionWorker_Order(t *testing.T) {
+	hub := NewHub(Config{}, nil)
+	worker := hub.AcquireWorker("session")
+	defer hub.ReleaseWorker(worker)
+
+	results := make(chan int, 10)
+	var wait sync.WaitGroup
+	for i := range 10 {
+		wait.Add(1)
+		if !worker.Submit(func() {
+			defer wait.Done()
+			results <- i
+		}) {
+			t.Fatalf("failed to submit job %d", i)
+		}
+	}
+	wait.Wait()
+	close(results)
+	expected := 0
+	for result := range results {
+		if result != expected {
+			t.Fatalf("expected job %d, got %d", expected, result)
+		}
+		expected++
+	}
+}
+
+func Test_SessionWorker_QueueFull(t *testing.T) {
+	hub := NewHub(Config{Worker: WorkerConfig{QueueSize: 1}}, nil)
+	worker := hub.AcquireWorker("session")
+	defer hub.ReleaseWorker(worker)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	worker.Submit(func() {
+		close(started)
+		<-release
+	})
+	<-started
+	if !worker.Submit(func() {}) {
+		t.Fatalf("expected a queued job to fit")
+	}
+	if worker.Submit(func() {}) {
+		t.Fatalf("expected a full queue to refuse the job")
+	}
+	close(release)
+}
+
+func Test_Hub_AcquireWorker(t *testing.T) {
+	hub := NewHub(Config{}, nil)
+	first := hub.AcquireWorker("session")
+	second := hub.AcquireWorker("session")
+	other := hub.AcquireWorker("other")
+	if first != second || first == other {
+		t.Fatalf("expected one worker per session")
+	}
+
+	hub.ReleaseWorker(first)
+	if hub.Workers["session"] != second {
+		t.Fatalf("expected worker to stay while referenced")
+	}
+	hub.ReleaseWorker(second)
+	if _, ok := hub.Workers["session"]; ok {
+		t.Fatalf("expected released worker to be removed")
+	}
+	if third := hub.AcquireWorker("session"); third == first {
+		t.Fatalf("expected a new worker after release")
+	}
+	hub.ReleaseWorker(other)
+}
+
+func Test_Connection_Schedule(t *testing.T) {
+	conn := NewConnection(nil, 1)
+	executed := make(chan struct{})
+	if !conn.Schedule(func() { close(executed) }) {
+		t.Fatalf("expected job to be scheduled")
+	}
+	if conn.Schedule(func() {}) {
+		t.Fatalf("expected a full outbox to refuse the job")
+	}
+
+	frame := <-conn.Outbox
+	frame.Execute()
+	select {
+	case <-executed:
+	case <-time.After(time.Second):
+		t.Fatalf("expected scheduled job to run")
+	}
+
+	conn.Close()
+	if conn.Schedule(func() {}) {
+		t.Fatalf("expected a closed connection to refuse the job")
+	}
+	if err := conn.WriteMessage(0, nil); err == nil {
+		t.Fatalf("expected a closed connection to refuse writes")
+	}
+}
@@ -0,0 +1,230 @@
+package wstest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	fastws "github.com/fasthttp/websocket"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
+)
+
+const (
+	DefaultTimeout = 2 * time.Second
+)
+
+type DialOption func(dial *dialSettings)
+
+type dialSettings struct {
+	Query     url.Values
+	Header    http.Header
+	Protocols []string
+}
+
+func WithSessionID(sessionID string) DialOption {
+	return func(dial *dialSettings) {
+		dial.Query["session_id"] = []string{sessionID}
+	}
+}
+
+func WithUserID(userID int64) DialOption {
+	return func(dial *dialSettings) {
+		dial.Query["user_id"] = []string{strconv.FormatInt(userID, 10)}
+	}
+}
+
+func WithAccessToken(token string) DialOption {
+	return func(dial *dialSettings) {
+		dial.Query["access_token"] = []string{token}
+	}
+}
+
+func WithProtocol(protocols ...string) DialOption {
+	return func(dial *dialSettings) {
+		dial.Protocols = append(dial.Protocols, protocols...)
+	}
+}
+
+type Client struct {
+	T      testing.TB
+	Conn   *fastws.Conn
+	Codec  wspkg.Codec
+	Frames chan wspkg.ResponseWrapper
+	Pongs  chan string
+
+	CloseError error
+	CloseMutex sync.Mutex
+	Done       chan struct{}
+}
+
+func newClient(t testing.TB, conn *fastws.Conn) *Client {
+	codec, err := wspkg.NewCodec(conn.Subprotocol())
+	if err != nil {
+		t.Fatalf("failed to resolve codec: %v", err)
+	}
+	client := &Client{
+		T:      t,
+		Conn:   conn,
+		Codec:  codec,
+		Frames: make(chan wspkg.ResponseWrapper, 64),
+		Pongs:  make(chan string, 8),
+		Done:   make(chan struct{}),
+	}
+	conn.SetPongHandler(func(data string) error {
+		select {
+		case client.Pongs <- data:
+		default:
+		}
+		return nil
+	})
+	go client.read()
+	t.Cleanup(func() { client.Conn.Close() })
+	return client
+}
+
+func (client *Client) read() {
+	defer close(client.Done)
+	defer close(client.Frames)
+	for {
+		_, raw, err := client.Conn.ReadMessage()
+		if err != nil {
+			client.CloseMutex.Lock()
+			client.CloseError = err
+			client.CloseMutex.Unlock()
+			return
+		}
+		var frame wspkg.ResponseWrapper
+		if err := client.Codec.Unmarshal(raw, &frame); err != nil {
+			client.T.Errorf("failed to decode frame %q: %v", raw, err)
+			continue
+		}
+		client.Frames <- frame
+	}
+}
+
+func (client *Client) Send(action wspkg.Action, payload any) {
+	client.T.Helper()
+	serialized, err := client.Codec.Marshal(wspkg.InboundFrame{Action: action, Payload: payload})
+	if err != nil {
+		client.T.Fatalf("failed to encode %v frame: %v", action, err)
+	}
+	if err := client.Conn.WriteMessage(client.Codec.MessageType(), serialized); err != nil {
+		client.T.Fatalf("failed to send %v frame: %v", action, err)
+	}
+}
+
+func (client *Client) Ping(timeout ...time.Duration) {
+	client.T.Helper()
+	deadline := time.Now().Add(timeoutOf(timeout))
+	if err := client.Conn.WriteControl(fastws.PingMessage, []byte("wstest"), deadline); err != nil {
+		client.T.Fatalf("failed to send ping: %v", err)
+	}
+	select {
+	case <-client.Pongs:
+	case <-time.After(time.Until(deadline)):
+		client.T.Fatalf("timed out waiting for pong")
+	}
+}
+
+func (client *Client) AwaitFunc(match func(frame wspkg.ResponseWrapper) bool, timeout ...time.Duration) wspkg.ResponseWrapper {
+	client.T.Helper()
+	expire := time.After(timeoutOf(timeout))
+	for {
+		select {
+		case frame, ok := <-client.Frames:
+			if !ok {
+				client.T.Fatalf("connection closed while awaiting frame: %v", client.closeError())
+			}
+			if match(frame) {
+				return frame
+			}
+		case <-expire:
+			client.T.Fatalf("timed out awaiting frame")
+		}
+	}
+}
+
+func (client *Client) Await(action wspkg.Action, timeout ...time.Duration) wspkg.ResponseWrapper {
+	client.T.Helper()
+	return client.AwaitFunc(func(frame wspkg.ResponseWrapper) bool {
+		return frame.Action == action
+	}, timeout...)
+}
+
+func (client *Client) AwaitPayload(action wspkg.Action, payload any, timeout ...time.Duration) wspkg.ResponseWrapper {
+	client.T.Helper()
+	expected := normalize(client.T, payload)
+	return client.AwaitFunc(func(frame wspkg.ResponseWrapper) bool {
+		return frame.Action == action && reflect.DeepEqual(normalize(client.T, frame.Payload), expected)
+	}, timeout...)
+}
+
+func (client *Client) ExpectSilence(duration time.Duration) {
+	client.T.Helper()
+	select {
+	case frame, ok := <-client.Frames:
+		if ok {
+			client.T.Fatalf("expected no frame, got %v(%v)", frame.Action, frame.Payload)
+		}
+		client.T.Fatalf("expected no frame, got close: %v", client.closeError())
+	case <-time.After(duration):
+	}
+}
+
+func (client *Client) ExpectClose(code int, timeout ...time.Duration) {
+	client.T.Helper()
+	select {
+	case <-client.Done:
+	case <-time.After(timeoutOf(timeout)):
+		client.T.Fatalf("timed out waiting for close %d", code)
+	}
+	err := client.closeError()
+	var closeErr *fastws.CloseError
+	if !errors.As(err, &closeErr) {
+		client.T.Fatalf("expected close %d, got %v", code, err)
+	}
+	if closeErr.Code != code {
+		client.T.Fatalf("expected close %d, got %d (%s)", code, closeErr.Code, closeErr.Text)
+	}
+}
+
+func (client *Client) Close() {
+	message := fastws.FormatCloseMessage(fastws.CloseNormalClosure, "")
+	client.Conn.WriteControl(fastws.CloseMessage, message, time.Now().Add(DefaultTimeout))
+	select {
+	case <-client.Done:
+	case <-time.After(DefaultTimeout):
+	}
+	client.Conn.Close()
+}
+
+func (client *Client) closeError() error {
+	client.CloseMutex.Lock()
+	defer client.CloseMutex.Unlock()
+	return client.CloseError
+}
+
+func normalize(t testing.TB, value any) any {
+	marshaled, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("failed to normalize %v: %v", value, err)
+	}
+	var normalized any
+	if err := json.Unmarshal(marshaled, &normalized); err != nil {
+		t.Fatalf("failed to normalize %v: %v", value, err)
+	}
+	return normalized
+}
+
+func timeoutOf(timeout []time.Duration) time.Duration {
+	if len(timeout) > 0 && timeout[0] > 0 {
+		return timeout[0]
+	}
+	return DefaultTimeout
+}
@@ -0,0 +1,162 @@
+package wstest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"testing"
+
+	fastws "github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/phayes/freeport"
+	"github.com/solutionchallenge/ondaum-server/pkg/jwt"
+	wspkg "github.com/solutionchallenge/ondaum-server/pkg/websocket"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+const (
+	DefaultPath = "/_ws/test"
+)
+
+type ServerOption func(server *Server)
+
+type Server struct {
+	T         testing.TB
+	App       *fiber.App
+	Hub       *wspkg.Hub
+	Path      string
+	Address   string
+	Config    wspkg.Config
+	Generator jwt.Generator
+	Network   bool
+	Listener  net.Listener
+}
+
+func WithConfig(config wspkg.Config) ServerOption {
+	return func(server *Server) {
+		server.Config = config
+	}
+}
+
+func WithHub(hub *wspkg.Hub) ServerOption {
+	return func(server *Server) {
+		server.Hub = hub
+	}
+}
+
+func WithPath(path string) ServerOption {
+	return func(server *Server) {
+		server.Path = path
+	}
+}
+
+func WithGenerator(generator jwt.Generator) ServerOption {
+	return func(server *Server) {
+		server.Generator = generator
+	}
+}
+
+func WithNetwork() ServerOption {
+	return func(server *Server) {
+		server.Network = true
+	}
+}
+
+func NewServer(t testing.TB, handler wspkg.Handler, options ...ServerOption) *Server {
+	t.Helper()
+	server := &Server{
+		T:    t,
+		App:  fiber.New(fiber.Config{DisableStartupMessage: true}),
+		Path: DefaultPath,
+	}
+	for _, option := range options {
+		option(server)
+	}
+	if server.Hub == nil {
+		server.Hub = wspkg.NewHub(server.Config, nil)
+	}
+
+	if server.Generator != nil {
+		if err := wspkg.EnableWebsocketCore(server.App, server.Path, server.Generator); err != nil {
+			t.Fatalf("failed to enable websocket core: %v", err)
+		}
+	} else {
+		server.App.Use(server.Path, identify)
+	}
+	wspkg.Install(server.App, server.Path, handler, server.Hub, server.Generator)
+
+	if server.Network {
+		port, err := freeport.GetFreePort()
+		if err != nil {
+			t.Fatalf("failed to get free port: %v", err)
+		}
+		server.Address = fmt.Sprintf("127.0.0.1:%d", port)
+		listener, err := net.Listen("tcp", server.Address)
+		if err != nil {
+			t.Fatalf("failed to listen on %s: %v", server.Address, err)
+		}
+		server.Listener = listener
+	} else {
+		server.Address = "wstest.local"
+		server.Listener = fasthttputil.NewInmemoryListener()
+	}
+	go server.App.Listener(server.Listener)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func (server *Server) Dial(t testing.TB, options ...DialOption) *Client {
+	t.Helper()
+	dial := &dialSettings{Query: url.Values{}}
+	for _, option := range options {
+		option(dial)
+	}
+	dialer := fastws.Dialer{
+		Subprotocols:     dial.Protocols,
+		HandshakeTimeout: DefaultTimeout,
+	}
+	if inmemory, ok := server.Listener.(*fasthttputil.InmemoryListener); ok {
+		dialer.NetDialContext = func(_ context.Context, _, _ string) (net.Conn, error) {
+			return inmemory.Dial()
+		}
+	}
+	target := url.URL{
+		Scheme:   "ws",
+		Host:     server.Address,
+		Path:     server.Path,
+		RawQuery: dial.Query.Encode(),
+	}
+	conn, response, err := dialer.Dial(target.String(), dial.Header)
+	if err != nil {
+		status := 0
+		if response != nil {
+			status = response.StatusCode
+		}
+		t.Fatalf("failed to dial %s (status %d): %v", target.String(), status, err)
+	}
+	return newClient(t, conn)
+}
+
+func (server *Server) Close() {
+	if err := server.App.Shutdown(); err != nil {
+		server.T.Logf("failed to shutdown test server: %v", err)
+	}
+}
+
+func identify(c *fiber.Ctx) error {
+	sessionID := c.Query("session_id")
+	if sessionID == "" {
+		sessionID = uuid.New().String()
+	}
+	c.Locals("X-Websocket-Session-ID", sessionID)
+	if userID := c.Query("user_id"); userID != "" {
+		if _, err := strconv.ParseInt(userID, 10, 64); err == nil {
+			c.Locals("X-Websocket-User-ID", userID)
+			c.Locals("X-Websocket-User-Metadata", map[string]any{})
+		}
+	}
+	return c.Next()
+}
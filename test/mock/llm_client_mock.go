@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pkg/llm/client.go
+//
+// Generated by this command:
+//
+//	mockgen -package=mock -destination=test/mock/llm_client_mock.go -source=pkg/llm/client.go -mock_names=Client=MockLLMClient
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	llm "github.com/solutionchallenge/ondaum-server/pkg/llm"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLLMClient is a mock of Client interface.
+type MockLLMClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockLLMClientMockRecorder
+	isgomock struct{}
+}
+
+// MockLLMClientMockRecorder is the mock recorder for MockLLMClient.
+type MockLLMClientMockRecorder struct {
+	mock *MockLLMClient
+}
+
+// NewMockLLMClient creates a new mock instance.
+func NewMockLLMClient(ctrl *gomock.Controller) *MockLLMClient {
+	mock := &MockLLMClient{ctrl: ctrl}
+	mock.recorder = &MockLLMClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLLMClient) EXPECT() *MockLLMClientMockRecorder {
+	return m.recorder
+}
+
+// Close mocks base method.
+func (m *MockLLMClient) Close(ids ...string) error {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range ids {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Close", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockLLMClientMockRecorder) Close(ids ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockLLMClient)(nil).Close), ids...)
+}
+
+// GetStatistics mocks base method.
+func (m *MockLLMClient) GetStatistics() llm.Statistics {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatistics")
+	ret0, _ := ret[0].(llm.Statistics)
+	return ret0
+}
+
+// GetStatistics indicates an expected call of GetStatistics.
+func (mr *MockLLMClientMockRecorder) GetStatistics() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatistics", reflect.TypeOf((*MockLLMClient)(nil).GetStatistics))
+}
+
+// RunActionPrompt mocks base method.
+func (m *MockLLMClient) RunActionPrompt(ctx context.Context, instructionIdentifier, promptIdentifier string, histories ...llm.Message) (llm.Message, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, instructionIdentifier, promptIdentifier}
+	for _, a := range histories {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RunActionPrompt", varargs...)
+	ret0, _ := ret[0].(llm.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunActionPrompt indicates an expected call of RunActionPrompt.
+func (mr *MockLLMClientMockRecorder) RunActionPrompt(ctx, instructionIdentifier, promptIdentifier any, histories ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, instructionIdentifier, promptIdentifier}, histories...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunActionPrompt", reflect.TypeOf((*MockLLMClient)(nil).RunActionPrompt), varargs...)
+}
+
+// StartConversation mocks base method.
+func (m *MockLLMClient) StartConversation(ctx context.Context, historyManager llm.HistoryManager, instructionIdentifier string, id ...string) (llm.Conversation, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, historyManager, instructionIdentifier}
+	for _, a := range id {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StartConversation", varargs...)
+	ret0, _ := ret[0].(llm.Conversation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartConversation indicates an expected call of StartConversation.
+func (mr *MockLLMClientMockRecorder) StartConversation(ctx, historyManager, instructionIdentifier any, id ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, historyManager, instructionIdentifier}, id...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartConversation", reflect.TypeOf((*MockLLMClient)(nil).StartConversation), varargs...)
+}